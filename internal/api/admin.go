@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// maxAdminKeys caps a listing, a full Redis can hold a lot of bars.
+const maxAdminKeys = 1000
+
+// adminOnly lets requests through that carry the admin token as X-Admin-Token.
+// Without a configured token the admin endpoints don't exist.
+func adminOnly(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := settings.Admin.Token
+		if token == "" {
+			writeError(w, r, &apiError{Status: http.StatusNotFound, Code: models.ErrNotFound, Key: "admin_disabled"})
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(token)) != 1 {
+			writeError(w, r, &apiError{Status: http.StatusUnauthorized, Code: models.ErrUnauthorized, Key: "admin_token_invalid"})
+			return
+		}
+		h(w, r)
+	}
+}
+
+// cachePattern is the key pattern of ?ticker=, every key without it. Listing
+// everything is fine, purging everything has to be asked for with all=true.
+func cachePattern(r *http.Request, purge bool) (string, *apiError) {
+	q := r.URL.Query()
+	if t := strings.TrimSpace(q.Get("ticker")); t != "" {
+		return cache.TickerPattern(t), nil
+	}
+	if purge && q.Get("all") != "true" {
+		return "", invalidParam("purge_target")
+	}
+	return "*", nil
+}
+
+// handleCacheInspect lists cached keys with their remaining TTL.
+func handleCacheInspect(w http.ResponseWriter, r *http.Request) {
+	pattern, apiErr := cachePattern(r, false)
+	if apiErr != nil {
+		writeError(w, r, apiErr)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	keys, err := scoreCache.Keys(ctx, pattern)
+	if err != nil {
+		writeError(w, r, &apiError{Status: http.StatusBadGateway, Code: models.ErrUnavailable, Key: "cache_unavailable", Args: []any{err.Error()}})
+		return
+	}
+	sort.Strings(keys)
+	resp := models.CacheKeysResponse{Count: len(keys), Keys: []models.CacheKey{}}
+	if len(keys) > maxAdminKeys {
+		keys = keys[:maxAdminKeys]
+		resp.Truncated = true
+	}
+	for _, k := range keys {
+		ttl, err := scoreCache.TTL(ctx, k)
+		if err != nil {
+			writeError(w, r, &apiError{Status: http.StatusBadGateway, Code: models.ErrUnavailable, Key: "cache_unavailable", Args: []any{err.Error()}})
+			return
+		}
+		secs := ttl.Seconds()
+		if ttl < 0 {
+			secs = -1
+		}
+		resp.Keys = append(resp.Keys, models.CacheKey{Key: k, TTLSeconds: secs})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleCachePurge deletes the keys of ?ticker=, or all of them with all=true.
+func handleCachePurge(w http.ResponseWriter, r *http.Request) {
+	pattern, apiErr := cachePattern(r, true)
+	if apiErr != nil {
+		writeError(w, r, apiErr)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	keys, err := scoreCache.Keys(ctx, pattern)
+	if err == nil {
+		var n int
+		n, err = scoreCache.Delete(ctx, keys...)
+		if err == nil {
+			writeJSON(w, http.StatusOK, models.CachePurgeResponse{Deleted: n})
+			return
+		}
+	}
+	writeError(w, r, &apiError{Status: http.StatusBadGateway, Code: models.ErrUnavailable, Key: "cache_unavailable", Args: []any{err.Error()}})
+}
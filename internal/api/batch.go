@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+const (
+	maxBatchItems    = 100
+	batchParallelism = 4
+	batchTimeout     = 60 * time.Second
+)
+
+type batchEntry struct {
+	item, defaults models.ScoreQuery
+}
+
+// get is the query parameter lookup for one batch entry, item fields
+// overriding the shared defaults.
+func (b batchEntry) get(name string) string {
+	pick := func(item, def string) string {
+		if item != "" {
+			return item
+		}
+		return def
+	}
+	pickInt := func(item, def int) string {
+		if item != 0 {
+			return strconv.Itoa(item)
+		}
+		if def != 0 {
+			return strconv.Itoa(def)
+		}
+		return ""
+	}
+
+	switch name {
+	case "ticker":
+		return b.item.Ticker
+	case "freq":
+		return pick(b.item.Freq, b.defaults.Freq)
+	case "start":
+		return pick(b.item.Start, b.defaults.Start)
+	case "end":
+		return pick(b.item.End, b.defaults.End)
+	case "asof":
+		return pick(b.item.Asof, b.defaults.Asof)
+	case "lang":
+		return pick(b.item.Lang, b.defaults.Lang)
+	case "labels":
+		return pick(b.item.Labels, b.defaults.Labels)
+	case "smooth":
+		return pick(b.item.Smooth, b.defaults.Smooth)
+	case "smooth_period":
+		return pickInt(b.item.SmoothPeriod, b.defaults.SmoothPeriod)
+	case "divergence":
+		return pick(b.item.Divergence, b.defaults.Divergence)
+	case "pivot":
+		return pickInt(b.item.Pivot, b.defaults.Pivot)
+	case "mtf":
+		if b.item.MTF || b.defaults.MTF {
+			return "true"
+		}
+		return ""
+	case "timeframes":
+		return pick(b.item.Timeframes, b.defaults.Timeframes)
+	case "window":
+		return pickInt(b.item.Window, b.defaults.Window)
+	case "tail":
+		return pickInt(b.item.Tail, b.defaults.Tail)
+	}
+	return ""
+}
+
+// batchResult is one computed entry, kept as results so it can be shaped
+// either as JSON or as export rows.
+type batchResult struct {
+	params  scoreParams
+	results []models.ScoreResult
+	err     *apiError
+}
+
+// handleBatch scores many tickers in one request with bounded parallelism.
+// With ?stream=true every item is written as an NDJSON line as soon as it is
+// done, in completion order. With an export format the rows of all tickers are
+// written in request order, each ticker as soon as everything before it is done.
+func handleBatch(w http.ResponseWriter, r *http.Request) {
+	format, apiErr := exportFormat(r)
+	if apiErr != nil {
+		writeError(w, r, apiErr)
+		return
+	}
+
+	var req models.BatchRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, r, invalidParam("invalid_batch", err.Error()))
+		return
+	}
+
+	entries := make([]batchEntry, 0, len(req.Tickers)+len(req.Items))
+	for _, t := range req.Tickers {
+		entries = append(entries, batchEntry{item: models.ScoreQuery{Ticker: t}, defaults: req.Defaults})
+	}
+	for _, it := range req.Items {
+		entries = append(entries, batchEntry{item: it, defaults: req.Defaults})
+	}
+	if len(entries) == 0 {
+		writeError(w, r, invalidParam("batch_empty"))
+		return
+	}
+	if len(entries) > maxBatchItems {
+		writeError(w, r, invalidParam("batch_too_large", maxBatchItems))
+		return
+	}
+	// One token per ticker, a batch of 50 is 50 lookups upstream
+	if !charge(w, r, len(entries)) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), batchTimeout)
+	defer cancel()
+
+	// A batch may outlive the server-wide write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Now().Add(batchTimeout + 5*time.Second))
+
+	stream := format == "" && r.URL.Query().Get("stream") == "true"
+	if stream {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	results, done := runBatch(ctx, entries)
+
+	if format != "" {
+		exportBatch(ctx, w, format, req.Defaults.Lang, entries, results, done)
+		return
+	}
+
+	items := make([]models.BatchItem, len(entries))
+	enc := json.NewEncoder(w)
+	for i := range done {
+		items[i] = batchItem(i, entries[i], results[i])
+		if stream {
+			_ = enc.Encode(items[i])
+			_ = rc.Flush()
+		}
+	}
+
+	if !stream {
+		writeJSON(w, http.StatusOK, models.BatchResponse{Items: items})
+	}
+}
+
+// exportBatch writes finished entries in request order as they become ready.
+// Failed tickers are left out of tabular formats and become error lines in NDJSON.
+func exportBatch(ctx context.Context, w http.ResponseWriter, format, lang string, entries []batchEntry, results []batchResult, done <-chan int) {
+	lang = langParam(lang)
+	ew, flush, err := startExport(ctx, w, format, lang, exportFilename("batch", time.Now().UTC().Format("20060102")))
+	if err != nil {
+		slog.WarnContext(ctx, "batch export failed", "err", err)
+		// Let the workers finish
+		for range done {
+		}
+		return
+	}
+
+	ready := make([]bool, len(entries))
+	next := 0
+	failed := false
+	for i := range done {
+		ready[i] = true
+		for ; next < len(entries) && ready[next]; next++ {
+			if failed {
+				continue
+			}
+			res := results[next]
+			var err error
+			if res.err != nil {
+				err = ew.WriteError(entries[next].item.Ticker, models.ErrorBody{Code: res.err.Code, Message: res.err.Message(langParam(entries[next].get("lang")))})
+			} else {
+				err = writeRows(ew, flush, res.params, res.results)
+			}
+			if err != nil {
+				// Client is gone; keep draining done so the workers can finish
+				slog.WarnContext(ctx, "batch export failed", "err", err)
+				failed = true
+				continue
+			}
+			flush()
+		}
+	}
+	if failed {
+		return
+	}
+	if err := ew.Close(); err != nil {
+		slog.WarnContext(ctx, "batch export failed", "err", err)
+	}
+}
+
+// runBatch scores entries with bounded parallelism. done yields the index of
+// every entry once its result is in place, and is closed after the last one.
+func runBatch(ctx context.Context, entries []batchEntry) (results []batchResult, done <-chan int) {
+	results = make([]batchResult, len(entries))
+	ch := make(chan int)
+	go func() {
+		sem := make(chan struct{}, batchParallelism)
+		var wg sync.WaitGroup
+		for i, e := range entries {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, e batchEntry) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] = scoreBatchEntry(ctx, e)
+				ch <- i
+			}(i, e)
+		}
+		wg.Wait()
+		close(ch)
+	}()
+	return results, ch
+}
+
+func scoreBatchEntry(ctx context.Context, e batchEntry) batchResult {
+	p, apiErr := parseScoreParams(e.get)
+	if apiErr != nil {
+		return batchResult{params: p, err: apiErr}
+	}
+	ctx, cancel := context.WithTimeout(ctx, settings.Score.FetchTimeout.D())
+	defer cancel()
+	results, _, apiErr := computeResults(ctx, p)
+	return batchResult{params: p, results: results, err: apiErr}
+}
+
+// batchItem shapes one computed entry for the JSON response.
+func batchItem(i int, e batchEntry, res batchResult) models.BatchItem {
+	item := models.BatchItem{Index: i, Ticker: e.item.Ticker}
+	if res.err != nil {
+		item.Error = &models.ErrorBody{Code: res.err.Code, Message: res.err.Message(langParam(e.get("lang")))}
+		return item
+	}
+	item.Result = buildResponse(res.params, res.results)
+	return item
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Responses smaller than this go out as is, compressing them costs more than it saves.
+const minCompressSize = 1024
+
+// compressible content types. XLSX is a zip already, event streams are left
+// alone so every event reaches the browser as soon as it is written.
+var compressible = map[string]bool{
+	"application/json":       true,
+	"application/x-ndjson":   true,
+	"text/csv":               true,
+	"text/html":              true,
+	"text/plain":             true,
+	"text/css":               true,
+	"application/javascript": true,
+}
+
+var (
+	gzipPool   = sync.Pool{New: func() interface{} { return gzip.NewWriter(io.Discard) }}
+	brotliPool = sync.Pool{New: func() interface{} { return brotli.NewWriterLevel(io.Discard, 4) }}
+)
+
+// compressMiddleware encodes responses with br or gzip, whichever the client
+// prefers (br on a tie). Handlers don't notice: flushing still streams.
+func compressMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		enc := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if enc == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, encoding: enc}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks br or gzip from an Accept-Encoding header, "" for neither.
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "br" && name != "gzip" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > bestQ || (q == bestQ && name == "br") {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// compressWriter holds back the status and the first bytes until it knows
+// whether the response is worth compressing.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     encoder // nil when passing through
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided || cw.status != 0 || code < 200 {
+		if cw.decided {
+			cw.ResponseWriter.WriteHeader(code)
+		}
+		return
+	}
+	cw.status = code
+	if !cw.eligible() {
+		cw.passThrough()
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		if !cw.eligible() {
+			cw.passThrough()
+		} else if len(cw.buf)+len(p) < minCompressSize {
+			cw.buf = append(cw.buf, p...)
+			return len(p), nil
+		} else {
+			cw.start()
+		}
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush commits to compressing, a flushed response is a streamed one.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		if cw.eligible() {
+			cw.start()
+		} else {
+			cw.passThrough()
+		}
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach deadlines of the real writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Close sends whatever is still held back and finishes the encoding.
+func (cw *compressWriter) Close() {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buf) == 0 {
+			return
+		}
+		cw.passThrough()
+	}
+	if cw.enc == nil {
+		return
+	}
+	_ = cw.enc.Close()
+	cw.enc.Reset(io.Discard)
+	if cw.encoding == "br" {
+		brotliPool.Put(cw.enc)
+	} else {
+		gzipPool.Put(cw.enc)
+	}
+}
+
+func (cw *compressWriter) eligible() bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" || cw.status == http.StatusNoContent || cw.status == http.StatusNotModified {
+		return false
+	}
+	ct, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	return compressible[ct]
+}
+
+func (cw *compressWriter) passThrough() {
+	cw.decided = true
+	if cw.status != 0 {
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+	if len(cw.buf) > 0 {
+		_, _ = cw.ResponseWriter.Write(cw.buf)
+		cw.buf = nil
+	}
+}
+
+func (cw *compressWriter) start() {
+	cw.decided = true
+	h := cw.Header()
+	h.Set("Content-Encoding", cw.encoding)
+	h.Del("Content-Length")
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if cw.encoding == "br" {
+		cw.enc = brotliPool.Get().(*brotli.Writer)
+	} else {
+		cw.enc = gzipPool.Get().(*gzip.Writer)
+	}
+	cw.enc.Reset(cw.ResponseWriter)
+	if len(cw.buf) > 0 {
+		_, _ = cw.enc.Write(cw.buf)
+		cw.buf = nil
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+	"github.com/iwanlebron/stock-analysis/internal/config"
+)
+
+// settings is the effective configuration, the defaults until SetConfig.
+var settings = config.Default()
+
+// SetConfig applies the server configuration. Call it before Handler and
+// SetCache, it resets the score cache to a memory one.
+func SetConfig(c config.Config) {
+	settings = c
+	scoreCache = cache.NewMemory(c.Cache.CleanupInterval.D())
+	applyRateLimit(c.RateLimit)
+}
+
+// SetCache replaces the score cache, e.g. with a shared Redis one.
+func SetCache(c cache.Cache) {
+	scoreCache = c
+}
+
+// handleConfig shows the effective settings, secrets masked.
+func handleConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, settings.Public())
+}
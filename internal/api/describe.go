@@ -0,0 +1,27 @@
+package api
+
+import (
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// describe returns the localized component list and method text shown next to a score.
+func describe(lang string, window int) (components []models.Component, method models.Method) {
+	components = make([]models.Component, 0, len(calc.Components))
+	for _, id := range calc.Components {
+		components = append(components, models.Component{
+			ID:          id,
+			Name:        i18n.T(lang, "component."+id+".name"),
+			Description: i18n.T(lang, "component."+id+".description"),
+			Detail:      i18n.T(lang, "component."+id+".detail"),
+			Weight:      calc.Weights[id],
+		})
+	}
+	method = models.Method{
+		ReferenceWindow: window,
+		Normalize:       i18n.T(lang, "method.normalize"),
+		Aggregate:       i18n.T(lang, "method.aggregate"),
+	}
+	return components, method
+}
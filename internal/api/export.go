@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/export"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// flushEvery is how many rows we buffer before pushing a chunk to the client.
+const flushEvery = 500
+
+var acceptFormats = map[string]string{
+	"text/csv":             export.CSV,
+	"application/x-ndjson": export.NDJSON,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": export.XLSX,
+}
+
+// exportFormat picks the export format from ?format= or, failing that, the Accept
+// header. An empty result means the regular JSON response.
+func exportFormat(r *http.Request) (string, *apiError) {
+	if f := r.URL.Query().Get("format"); f != "" {
+		switch f {
+		case "json":
+			return "", nil
+		case export.CSV, export.XLSX, export.NDJSON:
+			return f, nil
+		}
+		return "", invalidParam("invalid_format")
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if f, ok := acceptFormats[mt]; ok {
+			return f, nil
+		}
+	}
+	return "", nil
+}
+
+// startExport writes the download headers and returns the row writer. The
+// returned flush pushes buffered rows through to the client. The writer
+// starts the document right away, so an error is most likely the client
+// gone; the status is sent by then and the caller can only stop.
+func startExport(ctx context.Context, w http.ResponseWriter, format, lang, filename string) (export.Writer, func(), error) {
+	w.Header().Set("Content-Type", export.ContentTypes[format])
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+"."+format+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	ew, err := export.New(format, w, lang)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc := http.NewResponseController(w)
+	flush := func() {
+		if err := ew.Flush(); err != nil {
+			slog.WarnContext(ctx, "export flush failed", "err", err)
+			return
+		}
+		_ = rc.Flush()
+	}
+	return ew, flush, nil
+}
+
+// writeRows exports the visible window of one ticker's results.
+func writeRows(ew export.Writer, flush func(), p scoreParams, results []models.ScoreResult) error {
+	for i, r := range visible(p, results) {
+		if err := ew.WriteRow(export.Row{
+			Ticker: p.Ticker,
+			Date:   calendar.FormatBarTime(r.Date, p.Freq, p.Loc),
+			Result: r,
+		}); err != nil {
+			return err
+		}
+		if (i+1)%flushEvery == 0 {
+			flush()
+		}
+	}
+	return nil
+}
+
+// exportFilename is a download name safe for Content-Disposition.
+func exportFilename(parts ...string) string {
+	name := strings.Join(parts, "_")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+	"github.com/iwanlebron/stock-analysis/internal/config"
+	"github.com/iwanlebron/stock-analysis/internal/data/fake"
+)
+
+// goneWriter is a client that hung up: every write fails.
+type goneWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *goneWriter) Header() http.Header       { return w.header }
+func (w *goneWriter) WriteHeader(status int)    { w.status = status }
+func (w *goneWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+// A CSV export writes its BOM and header row at once, a failing write there
+// ends the response instead of the handler.
+func TestExportClientGone(t *testing.T) {
+	SetConfig(config.Default())
+	SetProvider(&fake.Provider{})
+	SetCache(cache.NewMemory(time.Minute))
+	Handler()
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     *http.Request
+	}{
+		{"score", handleFearGreed, httptest.NewRequest("GET", "/api/v1/fear-greed?ticker=AAPL&format=csv", nil)},
+		{"batch", handleBatch, httptest.NewRequest("POST", "/api/v1/scores:batch?format=csv", strings.NewReader(`{"tickers":["AAPL","MSFT"]}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &goneWriter{header: http.Header{}}
+			tt.handler(w, tt.req)
+			if w.status != http.StatusOK {
+				t.Errorf("status %d, want the 200 sent before the body", w.status)
+			}
+		})
+	}
+}
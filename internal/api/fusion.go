@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// fuse fills Timeframes and Fused of results, bars of p.Freq, with the score
+// each timeframe of p.Timeframes had at the close of every bar. A timeframe
+// that can't be fetched stays out of the blend.
+func fuse(ctx context.Context, p scoreParams, results []models.ScoreResult) {
+	if len(results) == 0 {
+		return
+	}
+	at := make([]time.Time, len(results))
+	for i, r := range results {
+		at[i] = calc.BarEnd(r.Date, p.Freq)
+	}
+	aligned := make([][]float64, len(p.Timeframes))
+	for k, tf := range p.Timeframes {
+		src, err := stampedScores(ctx, p, results, tf.Freq)
+		if err != nil {
+			slog.WarnContext(ctx, "timeframe unavailable", "ticker", p.Ticker, "freq", tf.Freq, "err", err)
+		}
+		aligned[k] = calc.Align(at, src)
+	}
+
+	scores := make([]float64, len(p.Timeframes))
+	for i := range results {
+		results[i].Timeframes = make(map[string]float64, len(p.Timeframes))
+		for k, tf := range p.Timeframes {
+			scores[k] = aligned[k][i]
+			results[i].Timeframes[tf.Freq] = scores[k]
+		}
+		results[i].Fused = calc.Fuse(scores, p.Timeframes)
+	}
+}
+
+// stampedScores scores p.Ticker in freq, each score dated with the close of
+// its bar. results are the scores in p.Freq, reused when freq is the same.
+func stampedScores(ctx context.Context, p scoreParams, results []models.ScoreResult, freq string) ([]calc.Stamped, error) {
+	if freq == p.Freq {
+		return calc.Stamp(results, freq), nil
+	}
+	cfg := calc.DefaultConfig
+	cfg.NormWindow = p.Window
+	cfg.Labels = p.Labels
+
+	// Warm up before the first bar of p.Freq, which is already before start
+	fetchFreq, from := freq, results[0].Date
+	if freq == "1wk" {
+		// Weekly bars come from daily ones, a year of them by default
+		fetchFreq = "1d"
+		cfg.NormWindow = max(p.Window/5, 10)
+	}
+	pf, err := fetchPrices(ctx, p.Ticker, calc.WarmupStart(from, freq), p.End, fetchFreq, p.Asof)
+	if err != nil {
+		return nil, err
+	}
+	if freq == "1wk" {
+		return calc.WeeklyAsOf(pf.Prices, cfg, p.Lang), nil
+	}
+	return calc.Stamp(calc.Compute(pf, cfg, p.Lang), freq), nil
+}
+
+// timeframeScores are the scores of every timeframe of r that has one.
+func timeframeScores(r models.ScoreResult) map[string]float64 {
+	out := make(map[string]float64, len(r.Timeframes))
+	for tf, s := range r.Timeframes {
+		if !math.IsNaN(s) {
+			out[tf] = s
+		}
+	}
+	return out
+}
+
+// fusionInfo reports the timeframes of a fused score, weights scaled to add
+// up to 1.
+func fusionInfo(tfs []calc.TimeframeWeight) *models.FusionInfo {
+	sum := 0.0
+	for _, t := range tfs {
+		sum += t.Weight
+	}
+	info := &models.FusionInfo{Timeframes: make([]models.TimeframeWeight, len(tfs))}
+	for i, t := range tfs {
+		info.Timeframes[i] = models.TimeframeWeight{Freq: t.Freq, Weight: t.Weight / sum}
+	}
+	return info
+}
@@ -0,0 +1,405 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+	"github.com/iwanlebron/stock-analysis/internal/logging"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+	pb "github.com/iwanlebron/stock-analysis/proto/feargreed/v1"
+)
+
+// GRPCServer serves FearGreedService on the same provider, cache, limits and
+// calc pipeline as Handler, plus the standard grpc.health.v1 service, which
+// reports NOT_SERVING once Drain is called. Call it after Handler.
+func GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptor),
+		grpc.ChainStreamInterceptor(streamInterceptor),
+	}, opts...)
+	s := grpc.NewServer(opts...)
+	pb.RegisterFearGreedServiceServer(s, grpcService{})
+
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+	go func() {
+		<-drainCh
+		hs.Shutdown()
+	}()
+	return s
+}
+
+type grpcService struct {
+	pb.UnimplementedFearGreedServiceServer
+}
+
+func (grpcService) GetScore(ctx context.Context, req *pb.GetScoreRequest) (*pb.GetScoreResponse, error) {
+	lang := callLang(ctx, req.GetLang())
+	ctx, cancel := context.WithTimeout(ctx, settings.Score.FetchTimeout.D())
+	defer cancel()
+
+	p, apiErr := parseScoreParams(batchEntry{item: scoreQuery(req)}.get)
+	if apiErr != nil {
+		return nil, grpcError(apiErr, lang, limitState{})
+	}
+	results, _, apiErr := computeResults(ctx, p)
+	if apiErr != nil {
+		return nil, grpcError(apiErr, lang, limitState{})
+	}
+	return scoreResponse(buildResponse(p, results)), nil
+}
+
+func (grpcService) GetHistory(ctx context.Context, req *pb.GetHistoryRequest) (*pb.GetHistoryResponse, error) {
+	get := func(name string) string {
+		switch name {
+		case "ticker":
+			return req.GetTicker()
+		case "freq":
+			return req.GetFreq()
+		case "start":
+			return req.GetStart()
+		case "end":
+			return req.GetEnd()
+		case "lang":
+			return req.GetLang()
+		}
+		return ""
+	}
+	p, snaps, apiErr := loadHistory(get)
+	if apiErr != nil {
+		return nil, grpcError(apiErr, callLang(ctx, req.GetLang()), limitState{})
+	}
+
+	h := historyResponse(p, snaps)
+	resp := &pb.GetHistoryResponse{Ticker: h.Ticker, Frequency: h.Frequency, Series: make([]*pb.PublishedScore, 0, len(h.Series))}
+	for _, s := range h.Series {
+		resp.Series = append(resp.Series, &pb.PublishedScore{
+			Score:       &pb.Score{Date: s.Date, Score: s.Score, Label: s.Label, Price: s.Price, Subscores: s.Subscores},
+			NormWindow:  int32(s.NormWindow),
+			Bars:        int32(s.Bars),
+			PublishedAt: s.PublishedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+func (grpcService) BatchGetScores(ctx context.Context, req *pb.BatchGetScoresRequest) (*pb.BatchGetScoresResponse, error) {
+	lang := callLang(ctx, req.GetDefaults().GetLang())
+	defaults := scoreQuery(req.GetDefaults())
+	entries := make([]batchEntry, 0, len(req.GetItems()))
+	for _, it := range req.GetItems() {
+		entries = append(entries, batchEntry{item: scoreQuery(it), defaults: defaults})
+	}
+	if len(entries) == 0 {
+		return nil, grpcError(invalidParam("batch_empty"), lang, limitState{})
+	}
+	if len(entries) > maxBatchItems {
+		return nil, grpcError(invalidParam("batch_too_large", maxBatchItems), lang, limitState{})
+	}
+	// One token per ticker, as over HTTP
+	if err := chargeGRPC(ctx, len(entries)); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, batchTimeout)
+	defer cancel()
+	results, done := runBatch(ctx, entries)
+	for range done {
+	}
+
+	resp := &pb.BatchGetScoresResponse{Items: make([]*pb.BatchItem, len(entries))}
+	for i, e := range entries {
+		item := batchItem(i, e, results[i])
+		out := &pb.BatchItem{Index: int32(i), Ticker: item.Ticker}
+		if item.Error != nil {
+			out.Outcome = &pb.BatchItem_Error{Error: &pb.Error{Code: item.Error.Code, Message: item.Error.Message}}
+		} else {
+			out.Outcome = &pb.BatchItem_Result{Result: scoreResponse(item.Result)}
+		}
+		resp.Items[i] = out
+	}
+	return resp, nil
+}
+
+func (grpcService) WatchScores(req *pb.WatchScoresRequest, ss pb.FearGreedService_WatchScoresServer) error {
+	tickers, freq, lang, apiErr := parseWatch(req.GetTickers(), req.GetFreq(), req.GetLang())
+	if apiErr != nil {
+		return grpcError(apiErr, callLang(ss.Context(), req.GetLang()), limitState{})
+	}
+
+	ctx := ss.Context()
+	updates := subscribe(ctx, tickers, freq)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-drainCh:
+			// Shutting down, the client reconnects to another instance
+			return status.Error(codes.Unavailable, i18n.T(lang, "error.shutting_down"))
+		case u := <-updates:
+			ev := streamEvent(u, lang)
+			if err := ss.Send(&pb.ScoreUpdate{
+				Ticker:     ev.Ticker,
+				Frequency:  ev.Frequency,
+				Date:       ev.Date,
+				Score:      ev.Score,
+				Label:      ev.Label,
+				Band:       ev.Band,
+				Regime:     ev.Regime,
+				Confidence: ev.Confidence,
+				Price:      ev.Price,
+				Subscores:  ev.Subscores,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// scoreQuery is the HTTP query equivalent of r, nil for all defaults.
+func scoreQuery(r *pb.GetScoreRequest) models.ScoreQuery {
+	return models.ScoreQuery{
+		Ticker: r.GetTicker(),
+		Freq:   r.GetFreq(),
+		Start:  r.GetStart(),
+		End:    r.GetEnd(),
+		Asof:   r.GetAsof(),
+		Window: int(r.GetWindow()),
+		Tail:   int(r.GetTail()),
+		Lang:   r.GetLang(),
+		Labels: r.GetLabels(),
+
+		Smooth:       r.GetSmooth(),
+		SmoothPeriod: int(r.GetSmoothPeriod()),
+
+		Divergence: r.GetDivergence(),
+		Pivot:      int(r.GetPivot()),
+
+		MTF:        r.GetMtf(),
+		Timeframes: r.GetTimeframes(),
+	}
+}
+
+func scoreResponse(r *models.APIResponse) *pb.GetScoreResponse {
+	resp := &pb.GetScoreResponse{Ticker: r.Ticker, Frequency: r.Frequency, Series: make([]*pb.SeriesPoint, len(r.Series))}
+	for i, s := range r.Series {
+		resp.Series[i] = &pb.SeriesPoint{Date: s.Date, Score: s.Score, Label: s.Label, Price: s.Price, Band: s.Band, Signal: s.Signal, Event: s.Event, Regime: s.Regime, Fused: s.Fused, Timeframes: s.Timeframes, Quality: dataQuality(s.Quality)}
+	}
+	if l := r.Latest; l != nil {
+		resp.Latest = &pb.Score{Date: l.Date, Score: l.Score, Label: l.Label, Price: l.Price, Subscores: r.LatestSubscores, Band: l.Band, Signal: l.Signal, Regime: l.Regime, Fused: l.Fused, Timeframes: l.Timeframes, Quality: dataQuality(l.Quality)}
+	}
+	if sm := r.Smoothing; sm != nil {
+		resp.Smoothing = &pb.Smoothing{Method: sm.Method, Period: int32(sm.Period)}
+	}
+	for _, d := range r.Divergences {
+		resp.Divergences = append(resp.Divergences, &pb.Divergence{
+			Kind: d.Kind, Indicator: d.Indicator, Start: d.Start, End: d.End, Confirmed: d.Confirmed,
+			StartPrice: d.StartPrice, EndPrice: d.EndPrice, StartValue: d.StartValue, EndValue: d.EndValue,
+			PriceChange: d.PriceChange, Strength: d.Strength,
+		})
+	}
+	if f := r.Fusion; f != nil {
+		resp.Fusion = &pb.Fusion{}
+		for _, t := range f.Timeframes {
+			resp.Fusion.Timeframes = append(resp.Fusion.Timeframes, &pb.TimeframeWeight{Freq: t.Freq, Weight: t.Weight})
+		}
+	}
+	for _, sp := range r.Regimes {
+		resp.Regimes = append(resp.Regimes, &pb.RegimeSpan{Regime: sp.Regime, Name: sp.Name, Start: sp.Start, End: sp.End, Bars: int32(sp.Bars)})
+	}
+	ls := r.LabelScheme
+	resp.LabelScheme = &pb.LabelScheme{Id: ls.ID, Hysteresis: ls.Hysteresis, MinDwell: int32(ls.MinDwell)}
+	for _, b := range ls.Bands {
+		resp.LabelScheme.Bands = append(resp.LabelScheme.Bands, &pb.LabelBand{Key: b.Key, Name: b.Name, From: b.From, To: b.To, Inclusive: b.Inclusive})
+	}
+	return resp
+}
+
+// grpcError maps an API error onto the nearest gRPC code. The JSON API's error
+// code travels as the ErrorInfo reason, a rate limit also gets RetryInfo.
+func grpcError(e *apiError, lang string, st limitState) error {
+	code := codes.Internal
+	switch e.Status {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		code = codes.Unavailable
+	case http.StatusGatewayTimeout:
+		code = codes.DeadlineExceeded
+	}
+	s := status.New(code, e.Message(lang))
+	details := []protoiface.MessageV1{&errdetails.ErrorInfo{Reason: e.Code, Domain: "stock-analysis"}}
+	if code == codes.ResourceExhausted {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Duration(seconds(st.retryAfter)) * time.Second)})
+	}
+	if ds, err := s.WithDetails(details...); err == nil {
+		s = ds
+	}
+	return s.Err()
+}
+
+// callClient identifies the caller from its metadata, like identify does from
+// HTTP headers: x-api-key or a bearer token, else the peer (or forwarded) IP.
+func callClient(ctx context.Context) (client, *apiError) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := first(md.Get("x-api-key"))
+	if auth := first(md.Get("authorization")); key == "" && len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		key = strings.TrimSpace(auth[7:])
+	}
+	remote := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remote = p.Addr.String()
+	}
+	return identifyKey(key, forwardedIP(remote, md.Get("x-forwarded-for")))
+}
+
+// callLang is the language of messages for a call: lang if set, else the
+// accept-language metadata, else the configured default.
+func callLang(ctx context.Context, lang string) string {
+	if l, ok := i18n.Match(lang); ok {
+		return l
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if l, ok := i18n.Negotiate(first(md.Get("accept-language"))); ok {
+		return l
+	}
+	return settings.Score.Lang
+}
+
+func first(v []string) string {
+	if len(v) == 0 {
+		return ""
+	}
+	return v[0]
+}
+
+// chargeGRPC takes cost tokens from the caller and sends the RateLimit
+// headers as response metadata.
+func chargeGRPC(ctx context.Context, cost int) error {
+	c, ok := ctx.Value(clientKey{}).(client)
+	if !ok || c.unlimited() {
+		return nil
+	}
+	st, apiErr := spend(c, cost)
+	if st.limit > 0 {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(
+			"ratelimit-policy", c.policy(),
+			"ratelimit-limit", strconv.Itoa(st.limit),
+			"ratelimit-remaining", strconv.Itoa(st.remaining),
+			"ratelimit-reset", strconv.Itoa(seconds(st.reset)),
+		))
+	}
+	if apiErr != nil {
+		return grpcError(apiErr, callLang(ctx, ""), st)
+	}
+	return nil
+}
+
+// startCall sets up the context of a call like the HTTP middlewares do:
+// request ID, trace, client and one token unless the method charges itself.
+func startCall(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := first(md.Get("x-request-id"))
+	if !validRequestID(id) {
+		id = logging.NewID(8)
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", id))
+	ctx = logging.WithRequestID(ctx, id)
+	ctx = logging.WithTrace(ctx, logging.StartSpan(first(md.Get("traceparent"))))
+
+	if strings.HasPrefix(method, "/grpc.health.v1.") {
+		return ctx, nil
+	}
+	c, apiErr := callClient(ctx)
+	if apiErr != nil {
+		return ctx, grpcError(apiErr, callLang(ctx, ""), limitState{})
+	}
+	ctx = context.WithValue(ctx, clientKey{}, c)
+	if method == pb.FearGreedService_BatchGetScores_FullMethodName {
+		return ctx, nil
+	}
+	return ctx, chargeGRPC(ctx, 1)
+}
+
+// logCall is the access log and metrics of a finished call.
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		level = slog.LevelError
+	}
+	remote := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remote = p.Addr.String()
+	}
+	slog.LogAttrs(ctx, level, "grpc request",
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+		slog.String("remote", remote),
+	)
+	grpcRequests.Inc(method, code.String())
+	grpcDuration.Observe(elapsed.Seconds(), method, code.String())
+}
+
+func unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	start := time.Now()
+	ctx, err = startCall(ctx, info.FullMethod)
+	defer func() { logCall(ctx, info.FullMethod, start, err) }()
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	start := time.Now()
+	ctx, err := startCall(ss.Context(), info.FullMethod)
+	defer func() { logCall(ctx, info.FullMethod, start, err) }()
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// contextStream is a ServerStream with the context set up by startCall.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// dataQuality is q in protobuf, nil for a bar without a score.
+func dataQuality(q *models.DataQuality) *pb.DataQuality {
+	if q == nil {
+		return nil
+	}
+	return &pb.DataQuality{Window: int32(q.Window), Samples: int32(q.Samples), Components: int32(q.Components), Coverage: q.Coverage, Confidence: q.Confidence}
+}
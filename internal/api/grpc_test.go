@@ -0,0 +1,267 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/iwanlebron/stock-analysis/internal/archive"
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/config"
+	"github.com/iwanlebron/stock-analysis/internal/data/fake"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+	pb "github.com/iwanlebron/stock-analysis/proto/feargreed/v1"
+)
+
+// listedProvider is the fake provider knowing a few tickers only, the others
+// fail like an unknown symbol at Yahoo.
+type listedProvider struct {
+	fake.Provider
+	listed map[string]bool
+}
+
+func (p *listedProvider) GetPrices(ctx context.Context, ticker string, start, end time.Time, freq string) (*models.PriceFrame, error) {
+	if !p.listed[ticker] {
+		return nil, fmt.Errorf("no data for %s", ticker)
+	}
+	return p.Provider.GetPrices(ctx, ticker, start, end, freq)
+}
+
+// grpcClient serves the package state over an in-memory connection.
+func grpcClient(t *testing.T) pb.FearGreedServiceClient {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Stream.Interval = config.Duration(50 * time.Millisecond)
+	SetConfig(cfg)
+	SetProvider(&listedProvider{listed: map[string]bool{"AAPL": true, "MSFT": true}})
+	SetCache(cache.NewMemory(time.Minute))
+
+	store, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ny := calendar.ExchangeFor("AAPL").Location
+	for i, day := range []string{"2024-03-01", "2024-03-04"} {
+		date, _ := time.ParseInLocation(time.DateOnly, day, ny)
+		if _, err := store.Append(models.Snapshot{
+			Ticker: "AAPL", Frequency: "1d", Date: date, Score: 40 + float64(i),
+			Labels: map[string]string{"en": "Fear"}, Price: 170, NormWindow: 252, Bars: 600,
+			PublishedAt: date.Add(21 * time.Hour),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	SetArchive(store)
+	t.Cleanup(func() { SetArchive(nil) })
+	Handler()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := GRPCServer()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewFearGreedServiceClient(conn)
+}
+
+// wantStatus checks err is a status with code and, if reason isn't empty, the
+// JSON API error code as ErrorInfo.
+func wantStatus(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != code {
+		t.Fatalf("err = %v, want %s", err, code)
+	}
+	if reason == "" {
+		return
+	}
+	for _, d := range s.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Reason == reason {
+			return
+		}
+	}
+	t.Errorf("details %v, want ErrorInfo %s", s.Details(), reason)
+}
+
+func TestGRPCGetScore(t *testing.T) {
+	c := grpcClient(t)
+	ctx := context.Background()
+
+	resp, err := c.GetScore(ctx, &pb.GetScoreRequest{Ticker: "AAPL", Lang: "en", Tail: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetTicker() != "AAPL" || resp.GetFrequency() != "1d" {
+		t.Errorf("ticker %q freq %q, want AAPL 1d", resp.GetTicker(), resp.GetFrequency())
+	}
+	if n := len(resp.GetSeries()); n != 30 {
+		t.Errorf("%d points, want the 30 of tail", n)
+	}
+	latest := resp.GetLatest()
+	if latest == nil || latest.GetScore() < 0 || latest.GetScore() > 100 || latest.GetLabel() == "" {
+		t.Fatalf("latest = %v, want a labeled score", latest)
+	}
+	if last := resp.GetSeries()[len(resp.GetSeries())-1]; last.GetScore() != latest.GetScore() || last.GetQuality() == nil {
+		t.Errorf("last point %v doesn't match latest %v", last, latest)
+	}
+
+	resp, err = c.GetScore(ctx, &pb.GetScoreRequest{Ticker: "AAPL", Divergence: "score", Pivot: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetDivergences()) == 0 {
+		t.Fatal("no divergences over two years")
+	}
+	for _, d := range resp.GetDivergences() {
+		// Three bars after the pivot, a day apart in the fake series
+		end, _ := time.Parse(time.DateOnly, d.GetEnd())
+		if want := end.AddDate(0, 0, 3).Format(time.DateOnly); d.GetConfirmed() != want {
+			t.Errorf("divergence ending %s confirmed %s, want %s", d.GetEnd(), d.GetConfirmed(), want)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		req    *pb.GetScoreRequest
+		code   codes.Code
+		reason string
+	}{
+		{"unknown symbol", &pb.GetScoreRequest{Ticker: "NOPE"}, codes.NotFound, models.ErrNotFound},
+		// Passed on to the provider, as over HTTP, which has no such bars
+		{"bad freq", &pb.GetScoreRequest{Ticker: "AAPL", Freq: "2m"}, codes.NotFound, models.ErrNotFound},
+		{"no ticker", &pb.GetScoreRequest{}, codes.InvalidArgument, models.ErrInvalidParameter},
+		{"bad date", &pb.GetScoreRequest{Ticker: "AAPL", Start: "2024-13-01"}, codes.InvalidArgument, models.ErrInvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.GetScore(ctx, tt.req)
+			wantStatus(t, err, tt.code, tt.reason)
+		})
+	}
+}
+
+func TestGRPCGetHistory(t *testing.T) {
+	c := grpcClient(t)
+	ctx := context.Background()
+
+	resp, err := c.GetHistory(ctx, &pb.GetHistoryRequest{Ticker: "AAPL", Lang: "en", Start: "2024-03-02"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(resp.GetSeries()); n != 1 {
+		t.Fatalf("%d snapshots, want the one from start on", n)
+	}
+	s := resp.GetSeries()[0]
+	if s.GetScore().GetDate() != "2024-03-04" || s.GetScore().GetScore() != 41 || s.GetNormWindow() != 252 || s.GetPublishedAt() == "" {
+		t.Errorf("snapshot = %v", s)
+	}
+
+	if resp, err := c.GetHistory(ctx, &pb.GetHistoryRequest{Ticker: "NOPE"}); err != nil || len(resp.GetSeries()) != 0 {
+		t.Errorf("unknown symbol = %v, %v, want an empty history", resp, err)
+	}
+	_, err = c.GetHistory(ctx, &pb.GetHistoryRequest{Ticker: "AAPL", End: "yesterday"})
+	wantStatus(t, err, codes.InvalidArgument, models.ErrInvalidParameter)
+
+	SetArchive(nil)
+	_, err = c.GetHistory(ctx, &pb.GetHistoryRequest{Ticker: "AAPL"})
+	wantStatus(t, err, codes.Unavailable, models.ErrUnavailable)
+}
+
+func TestGRPCBatchGetScores(t *testing.T) {
+	c := grpcClient(t)
+	ctx := context.Background()
+
+	resp, err := c.BatchGetScores(ctx, &pb.BatchGetScoresRequest{
+		Defaults: &pb.GetScoreRequest{Lang: "en", Tail: 5},
+		Items: []*pb.GetScoreRequest{
+			{Ticker: "AAPL"},
+			{Ticker: "NOPE"},
+			{Ticker: "MSFT", Freq: "1h", Tail: 3},
+			{Ticker: "AAPL", Freq: "2m"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := resp.GetItems()
+	if len(items) != 4 {
+		t.Fatalf("%d items, want 4", len(items))
+	}
+	for i, it := range items {
+		if it.GetIndex() != int32(i) {
+			t.Errorf("item %d has index %d", i, it.GetIndex())
+		}
+	}
+	if r := items[0].GetResult(); r == nil || len(r.GetSeries()) != 5 {
+		t.Errorf("AAPL = %v, want 5 points from the defaults", items[0])
+	}
+	if e := items[1].GetError(); e == nil || e.GetCode() != models.ErrNotFound {
+		t.Errorf("NOPE = %v, want not_found", items[1])
+	}
+	if r := items[2].GetResult(); r == nil || r.GetFrequency() != "1h" || len(r.GetSeries()) != 3 {
+		t.Errorf("MSFT 1h = %v, want 3 hourly points", items[2])
+	}
+	if e := items[3].GetError(); e == nil || e.GetCode() != models.ErrNotFound {
+		t.Errorf("bad freq = %v, want not_found", items[3])
+	}
+
+	_, err = c.BatchGetScores(ctx, &pb.BatchGetScoresRequest{})
+	wantStatus(t, err, codes.InvalidArgument, models.ErrInvalidParameter)
+	_, err = c.BatchGetScores(ctx, &pb.BatchGetScoresRequest{Items: make([]*pb.GetScoreRequest, maxBatchItems+1)})
+	wantStatus(t, err, codes.InvalidArgument, models.ErrInvalidParameter)
+}
+
+func TestGRPCWatchScores(t *testing.T) {
+	c := grpcClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watch, err := c.WatchScores(ctx, &pb.WatchScoresRequest{Tickers: []string{"aapl"}, Lang: "en"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := watch.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.GetTicker() != "AAPL" || u.GetFrequency() != "1d" || u.Score == nil || u.GetLabel() == "" || u.GetConfidence() == "" {
+		t.Errorf("update = %v, want a scored AAPL bar", u)
+	}
+	if len(u.GetSubscores()) == 0 {
+		t.Error("update without subscores")
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.WatchScoresRequest
+	}{
+		{"bad freq", &pb.WatchScoresRequest{Tickers: []string{"AAPL"}, Freq: "1wk"}},
+		{"no tickers", &pb.WatchScoresRequest{Tickers: []string{" "}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watch, err := c.WatchScores(ctx, tt.req)
+			if err == nil {
+				_, err = watch.Recv()
+			}
+			wantStatus(t, err, codes.InvalidArgument, models.ErrInvalidParameter)
+		})
+	}
+}
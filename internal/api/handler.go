@@ -3,53 +3,103 @@ package api
 import (
 	"context"
 	"embed"
-	"encoding/json"
-	"fmt"
 	"html/template"
 	"log"
-	"math"
-	"net"
+	"log/slog"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
-	"stock-analysis/internal/calc"
-	"stock-analysis/internal/data"
-	"stock-analysis/internal/models"
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+	"github.com/iwanlebron/stock-analysis/internal/data"
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+	"github.com/iwanlebron/stock-analysis/internal/logging"
+	"github.com/iwanlebron/stock-analysis/internal/metrics"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+	"github.com/iwanlebron/stock-analysis/internal/stream"
 
-	"github.com/patrickmn/go-cache"
+	gocache "github.com/patrickmn/go-cache"
 )
 
 //go:embed templates/*.html templates/partials/*.html
 var templateFS embed.FS
 
 var templates *template.Template
-var memCache *cache.Cache
-var rateCache *cache.Cache
+
+// uiStrings are the web UI messages of every locale, for the page script.
+var uiStrings = map[string]map[string]any{}
+
+// scoreCache holds computed score series, memory unless SetCache says otherwise.
+var scoreCache cache.Cache
+var rateCache *gocache.Cache
+
+// provider is where bars come from. It may also implement models.AsOfProvider.
+var provider models.Provider = data.NewYahooProvider()
+
+// SetProvider replaces the default Yahoo provider, e.g. with a recording bar store.
+func SetProvider(p models.Provider) {
+	provider = p
+}
 
 func init() {
 	var err error
-	templates, err = template.ParseFS(templateFS, "templates/*.html", "templates/partials/*.html")
+	templates, err = template.New("").Funcs(template.FuncMap{"t": i18n.T}).
+		ParseFS(templateFS, "templates/*.html", "templates/partials/*.html")
 	if err != nil {
 		log.Fatal("Error parsing templates:", err)
 	}
 
-	// Create a cache with a default expiration time of 5 minutes, and which
-	// purges expired items every 10 minutes
-	memCache = cache.New(5*time.Minute, 10*time.Minute)
-	rateCache = cache.New(2*time.Minute, 5*time.Minute)
+	for _, lang := range i18n.Locales() {
+		uiStrings[lang] = i18n.Tree(lang, "ui")
+		uiStrings[lang]["regimes"] = i18n.Tree(lang, "regime")
+	}
+
+	scoreCache = cache.NewMemory(settings.Cache.CleanupInterval.D())
+	rateCache = gocache.New(2*time.Minute, 5*time.Minute)
 }
 
 func Handler() http.Handler {
+	streamHub = stream.NewHub(provider, settings.Stream.Interval.D(), defaultCalcConfig())
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", handleIndex)
-	mux.HandleFunc("/healthz", handleHealthz)
-	mux.HandleFunc("/fear-greed", handleFearGreed)
-	return loggingMiddleware(rateLimitMiddleware(mux))
+	mux.HandleFunc("GET /livez", handleLivez)
+	mux.HandleFunc("GET /readyz", handleReadyz)
+	// Older probes, same as /livez
+	mux.HandleFunc("/healthz", handleLivez)
+
+	mux.HandleFunc("GET /api/v1/fear-greed", handleFearGreed)
+	mux.HandleFunc("GET /api/v1/fear-greed/history", handleHistory)
+	mux.HandleFunc("GET /api/v1/fear-greed/point-in-time", handlePointInTime)
+	mux.HandleFunc("POST /api/v1/scores:batch", handleBatch)
+	mux.HandleFunc("GET /api/v1/stream", handleStream)
+	mux.HandleFunc("GET /api/v1/config", handleConfig)
+	mux.HandleFunc("GET /api/v1/openapi.json", handleOpenAPI)
+
+	// Unversioned paths from before /api/v1, kept for existing clients
+	mux.Handle("GET /fear-greed", deprecated("/api/v1/fear-greed", handleFearGreed))
+	mux.Handle("GET /fear-greed/history", deprecated("/api/v1/fear-greed/history", handleHistory))
+	mux.Handle("GET /fear-greed/point-in-time", deprecated("/api/v1/fear-greed/point-in-time", handlePointInTime))
+
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.HandleFunc("GET /admin/cache", adminOnly(handleCacheInspect))
+	mux.HandleFunc("DELETE /admin/cache", adminOnly(handleCachePurge))
+
+	return requestIDMiddleware(loggingMiddleware(mux, compressMiddleware(rateLimitMiddleware(mux))))
 }
 
-func loggingMiddleware(next http.Handler) http.Handler {
+// deprecated marks a legacy route and points clients to its versioned successor.
+func deprecated(successor string, h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+		h(w, r)
+	})
+}
+
+// loggingMiddleware logs every request and records it in the HTTP metrics,
+// labelled by the mux pattern so paths with tickers don't explode cardinality.
+func loggingMiddleware(mux *http.ServeMux, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		// Wrap ResponseWriter to capture status code
@@ -57,57 +107,57 @@ func loggingMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(ww, r)
 
-		log.Printf("%s %s %d %s", r.Method, r.URL.String(), ww.statusCode, time.Since(start))
-	})
-}
-
-func rateLimitMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/fear-greed" {
-			next.ServeHTTP(w, r)
-			return
+		elapsed := time.Since(start)
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
 		}
 
-		ip := r.Header.Get("X-Forwarded-For")
-		if ip != "" {
-			if i := strings.Index(ip, ","); i != -1 {
-				ip = strings.TrimSpace(ip[:i])
-			} else {
-				ip = strings.TrimSpace(ip)
-			}
-		} else {
-			host, _, err := net.SplitHostPort(r.RemoteAddr)
-			if err == nil {
-				ip = host
-			} else {
-				ip = r.RemoteAddr
-			}
+		level := slog.LevelInfo
+		if ww.statusCode >= 500 {
+			level = slog.LevelError
 		}
+		slog.LogAttrs(r.Context(), level, "request",
+			slog.String("method", r.Method),
+			slog.String("url", redactedURL(r.URL)),
+			slog.String("route", route),
+			slog.Int("status", ww.statusCode),
+			slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+			slog.String("remote", r.RemoteAddr),
+		)
+		status := strconv.Itoa(ww.statusCode)
+		httpRequests.Inc(route, r.Method, status)
+		httpDuration.Observe(elapsed.Seconds(), route, r.Method, status)
+	})
+}
 
-		key := "rl:" + ip
-		if _, found := rateCache.Get(key); !found {
-			rateCache.Set(key, int64(1), time.Minute)
-		} else {
-			_ = rateCache.Increment(key, 1)
+// requestIDMiddleware gives every request an ID, taken from X-Request-ID when
+// the caller sent a sane one, and continues the caller's W3C trace. Both go
+// into the request context so every log line of the request carries them.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = logging.NewID(8)
 		}
+		w.Header().Set("X-Request-ID", id)
 
-		v, _ := rateCache.Get(key)
-		var count int64
-		if vv, ok := v.(int64); ok {
-			count = vv
-		}
+		ctx := logging.WithRequestID(r.Context(), id)
+		ctx = logging.WithTrace(ctx, logging.StartSpan(r.Header.Get("traceparent")))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
 
-		if count > 60 {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusTooManyRequests)
-			_ = json.NewEncoder(w).Encode(map[string]string{
-				"detail": "请求过于频繁，请稍后再试",
-			})
-			return
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
 		}
-
-		next.ServeHTTP(w, r)
-	})
+	}
+	return true
 }
 
 type responseWriterWrapper struct {
@@ -120,11 +170,16 @@ func (w *responseWriterWrapper) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
-func handleHealthz(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]string{
-		"status": "ok",
-	})
+// Unwrap lets http.ResponseController reach Flush and deadlines of the real writer.
+func (w *responseWriterWrapper) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// indexPage is the data of index.html.
+type indexPage struct {
+	Lang    string
+	Locales []string
+	Strings map[string]map[string]any
 }
 
 func handleIndex(w http.ResponseWriter, r *http.Request) {
@@ -133,334 +188,93 @@ func handleIndex(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Add("Vary", "Accept-Language")
 
-	err := templates.ExecuteTemplate(w, "index.html", nil)
+	// The page starts in the browser's language, the script may switch
+	err := templates.ExecuteTemplate(w, "index.html", indexPage{
+		Lang:    requestLang(r),
+		Locales: i18n.Locales(),
+		Strings: uiStrings,
+	})
 	if err != nil {
-		log.Printf("Error executing template: %v", err)
+		slog.ErrorContext(r.Context(), "template failed", "err", err)
 		http.Error(w, "Internal Server Error", 500)
 	}
 }
 
 func handleFearGreed(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), settings.Score.FetchTimeout.D())
 	defer cancel()
 
-	q := r.URL.Query()
-	ticker := q.Get("ticker")
-	if ticker == "" {
-		http.Error(w, `{"detail":"Ticker required"}`, 400)
+	p, apiErr := parseScoreParams(r.URL.Query().Get)
+	if apiErr != nil {
+		writeError(w, r, apiErr)
 		return
 	}
 
-	freq := q.Get("freq")
-	if freq == "" {
-		freq = "1d"
-	}
-
-	startStr := q.Get("start")
-	var start time.Time
-	if startStr != "" {
-		start, _ = time.Parse("2006-01-02", startStr)
-	}
-
-	// Adjust start date to fetch earlier data for warmup (e.g. MA60 needs 60 bars)
-	// We add buffer based on window.
-	// To be safe, we fetch (window * 2) extra days.
-	// For daily: 252 * 2 = ~500 days (approx 2 years)
-	fetchStart := start
-	if !start.IsZero() {
-		if freq == "1d" {
-			// Approx 2 years buffer for daily
-			// This ensures even if user asks for data starting today, we have enough history to compute indicators
-			fetchStart = start.AddDate(-2, 0, 0)
-		} else {
-			// For hourly, maybe 2 months buffer
-			fetchStart = start.AddDate(0, -2, 0)
-		}
+	format, apiErr := exportFormat(r)
+	if apiErr != nil {
+		writeError(w, r, apiErr)
+		return
 	}
 
-	lang := q.Get("lang")
-	if lang == "" {
-		lang = "zh"
+	results, hit, apiErr := computeResults(ctx, p)
+	if apiErr != nil {
+		writeError(w, r, apiErr)
+		return
 	}
 
-	// Window
-	windowStr := q.Get("window")
-	window := 252
-	if windowStr != "" {
-		if v, err := strconv.Atoi(windowStr); err == nil {
-			window = v
-		}
+	if hit {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		w.Header().Set("X-Cache", "MISS")
 	}
-
-	tail := 600
-	if tailStr := q.Get("tail"); tailStr != "" {
-		if v, err := strconv.Atoi(tailStr); err == nil {
-			tail = v
-		}
+	now := time.Now()
+	w.Header().Add("Vary", "Accept")
+	setCacheControl(w, r, p.Ticker, p.Freq, rangeEnd(p.End, p.Asof), now)
+	if notModified(w, r, scoreETag(p.cacheKey(), format, results), lastModified(results, p.Freq, p.Loc, now)) {
+		return
 	}
-
-	// Cache Key
-	cacheKey := fmt.Sprintf("%s-%s-%s-%s-%d-%d", ticker, freq, startStr, lang, window, tail)
-	if cachedResp, found := memCache.Get(cacheKey); found {
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("X-Cache", "HIT")
-		json.NewEncoder(w).Encode(cachedResp)
+	if format == "" {
+		writeJSON(w, http.StatusOK, buildResponse(p, results))
 		return
 	}
 
-	// Fetch Data
-	log.Printf("Fetching data for %s (Start: %s, Freq: %s)", ticker, startStr, freq)
-	provider := data.NewYahooProvider()
-	pf, err := provider.GetPrices(ctx, ticker, fetchStart, time.Time{}, freq)
+	ew, flush, err := startExport(ctx, w, format, p.Lang, exportFilename(p.Ticker, p.Freq))
 	if err != nil {
-		log.Printf("Error fetching data for %s: %v", ticker, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(404)
-		json.NewEncoder(w).Encode(map[string]string{
-			"detail": "未找到股票代码或暂无数据：" + ticker + " (" + err.Error() + ")",
-		})
+		slog.WarnContext(ctx, "export failed", "ticker", p.Ticker, "err", err)
 		return
 	}
-
-	// Compute
-	log.Printf("Computing indicators for %s (%d bars)", ticker, len(pf.Prices))
-	cfg := calc.DefaultConfig
-	cfg.NormWindow = window
-	results := calc.Compute(pf, cfg, lang)
-
-	// Find start index based on user request "start" time
-	// We calculated results starting from fetchStart (which is start - buffer).
-	// Now we want to find the index that corresponds to the user's requested 'start'.
-	startIdx := 0
-	if !start.IsZero() {
-		for i, r := range results {
-			// Find the first date >= user requested start
-			if !r.Date.Before(start) {
-				startIdx = i
-				break
-			}
-		}
-	} else {
-		// If no start provided, default logic (e.g. tail)
-		startIdx = len(results) - tail
-		if startIdx < 0 {
-			startIdx = 0
-		}
-	}
-
-	// Ensure bounds
-	if startIdx >= len(results) {
-		startIdx = len(results) - 1
-	}
-	if startIdx < 0 {
-		startIdx = 0
+	if err := writeRows(ew, flush, p, results); err != nil {
+		slog.WarnContext(ctx, "export failed", "ticker", p.Ticker, "err", err)
+		return
 	}
-
-	// Fix NaNs in series
-	// We need custom serialization or pre-process.
-	// Since struct has float64, we can't put null.
-	// Let's use 0 for simplicity or modify struct to *float64
-	// Let's modify struct logic inline:
-
-	type SafeScore struct {
-		Date  string   `json:"date"`
-		Score *float64 `json:"score"` // Pointer allows null
-		Label string   `json:"label"`
-		Price float64  `json:"price"` // Added Price
+	if err := ew.Close(); err != nil {
+		slog.WarnContext(ctx, "export failed", "ticker", p.Ticker, "err", err)
 	}
+}
 
-	safeSeries := make([]SafeScore, 0, len(results)-startIdx)
-	for i := startIdx; i < len(results); i++ {
-		r := results[i]
-		var s *float64
-		if !math.IsNaN(r.Score) {
-			v := r.Score
-			s = &v
-		}
-		safeSeries = append(safeSeries, SafeScore{
-			Date:  r.Date.Format("2006-01-02"),
-			Score: s,
-			Label: r.Label,
-			Price: r.Price, // Pass Price to frontend
-		})
+// fetchPrices loads bars for ticker up to end (zero for now). With a non-zero asof
+// the frame is also cut at asof and, when the provider keeps bar revisions,
+// corrections made later are ignored.
+func fetchPrices(ctx context.Context, ticker string, start, end time.Time, freq string, asof time.Time) (*models.PriceFrame, error) {
+	if !asof.IsZero() && (end.IsZero() || asof.Before(end)) {
+		end = asof
 	}
-
-	// Latest
-	var latest *models.SimpleScore
-	var latestSubscores map[string]float64
-
-	if len(results) > 0 {
-		last := results[len(results)-1]
-		if !math.IsNaN(last.Score) {
-			latest = &models.SimpleScore{
-				Date:  last.Date.Format("2006-01-02"),
-				Score: last.Score,
-				Label: last.Label,
-				Price: last.Price, // Added Price
-			}
-
-			latestSubscores = map[string]float64{
-				"trend":      last.Values.Trend,
-				"momentum":   last.Values.Momentum,
-				"rsi":        last.Values.RSI,
-				"macd":       last.Values.MACD,
-				"drawdown":   last.Values.Drawdown,
-				"volatility": last.Values.Vol,
-				"mfi":        last.Values.MFI,
-				"bb_pct_b":   last.Values.BB,
-			}
+	if ap, ok := provider.(models.AsOfProvider); ok && !asof.IsZero() {
+		h, err := ap.GetHistory(ctx, ticker, start, end, freq)
+		if err != nil {
+			return nil, err
 		}
+		return h.AsOf(asof).Truncate(end), nil
 	}
-
-	var components []models.Component
-	var method map[string]interface{}
-
-	if lang == "en" {
-		components = []models.Component{
-			{
-				ID:          "trend",
-				Name:        "Trend Strength",
-				Description: "Price vs MA20/60 position",
-				Detail:      "Trend Strength measures the current price relative to long-term (60-day) and medium-term (20-day) moving averages. Price above MAs indicates strong uptrend (Greed).",
-				Weight:      0.15,
-			},
-			{
-				ID:          "momentum",
-				Name:        "Momentum",
-				Description: "20-day return, short-term power",
-				Detail:      "Momentum is based on the cumulative return over the past 20 trading days. Higher positive returns indicate stronger upward momentum (Greed).",
-				Weight:      0.15,
-			},
-			{
-				ID:          "rsi",
-				Name:        "RSI",
-				Description: "Relative Strength Index (14D)",
-				Detail:      "RSI measures the speed and change of price movements. RSI > 70 is considered overbought (Extreme Greed), while RSI < 30 is oversold (Extreme Fear).",
-				Weight:      0.10,
-			},
-			{
-				ID:          "macd",
-				Name:        "MACD",
-				Description: "MACD Histogram, momentum shift",
-				Detail:      "The MACD histogram reflects the convergence and divergence of trends. Expanding positive values indicate strengthening upward momentum.",
-				Weight:      0.10,
-			},
-			{
-				ID:          "drawdown",
-				Name:        "Drawdown",
-				Description: "Drop from 252-day high",
-				Detail:      "Drawdown calculates the percentage drop from the highest price in the past 252 trading days. Smaller drawdown indicates a stronger market.",
-				Weight:      0.10,
-			},
-			{
-				ID:          "volatility",
-				Name:        "Volatility",
-				Description: "20-day realized volatility",
-				Detail:      "Volatility is based on the standard deviation of returns over 20 days. Spikes in volatility often accompany market panic (Fear).",
-				Weight:      0.10,
-			},
-			{
-				ID:          "mfi",
-				Name:        "Money Flow (MFI)",
-				Description: "Volume-weighted RSI (14D)",
-				Detail:      "MFI incorporates both price and volume to measure buying and selling pressure. It is often a leading indicator for reversals compared to standard RSI.",
-				Weight:      0.15,
-			},
-			{
-				ID:          "bb_pct_b",
-				Name:        "Bollinger %B",
-				Description: "Price vs Bollinger Bands",
-				Detail:      "Bollinger %B quantifies a security's price relative to the upper and lower Bollinger Bands. %B > 1 indicates price is above the upper band (Greed/Overbought).",
-				Weight:      0.15,
-			},
-		}
-		method = map[string]interface{}{
-			"reference_window_trading_days": window,
-			"normalize":                     "For each sub-indicator, calculate its rolling percentile within the reference window and map it to a 0-100 score.",
-			"aggregate":                     "Total score is the weighted average of available sub-scores: sum(score_i * w_i) / sum(w_i).",
-		}
-	} else {
-		// Default Chinese
-		components = []models.Component{
-			{
-				ID:          "trend",
-				Name:        "趋势强度",
-				Description: "价格相对均线(MA20/60)的位置，越高越强",
-				Detail:      "趋势强度衡量当前价格相对于长期（60日）和中期（20日）均线的位置。价格位于均线上方表明上升趋势强劲（贪婪），反之则为下降趋势（恐惧）。",
-				Weight:      0.15,
-			},
-			{
-				ID:          "momentum",
-				Name:        "动量",
-				Description: "20日收益率，反映短期冲力",
-				Detail:      "动量指标基于过去 20 个交易日的累计收益率。正收益率越高表示上涨动能越强，可能引发贪婪情绪；负收益率表示下跌动能。",
-				Weight:      0.15,
-			},
-			{
-				ID:          "rsi",
-				Name:        "RSI",
-				Description: "相对强弱指标(14日)，反映超买超卖",
-				Detail:      "相对强弱指数（RSI）衡量价格变动的速度和幅度。RSI > 70 通常被视为超买（极度贪婪），而 RSI < 30 则被视为超卖（极度恐惧）。",
-				Weight:      0.10,
-			},
-			{
-				ID:          "macd",
-				Name:        "MACD",
-				Description: "MACD柱状图，反映动能变化",
-				Detail:      "MACD 柱状图反映了短期和长期趋势的聚合与分离。正值扩大表示上涨动能增强，负值扩大表示下跌动能增强。",
-				Weight:      0.10,
-			},
-			{
-				ID:          "drawdown",
-				Name:        "回撤压力",
-				Description: "距离252日高点的跌幅，越小越好",
-				Detail:      "回撤压力计算当前价格距离过去 252 个交易日（一年）最高点的跌幅。回撤越小，市场越强势；回撤越大，市场恐慌情绪越重。",
-				Weight:      0.10,
-			},
-			{
-				ID:          "volatility",
-				Name:        "波动率",
-				Description: "20日实现波动率，越低越稳定",
-				Detail:      "波动率基于 20 日历史价格的标准差。波动率飙升通常伴随着市场恐慌（恐惧），而低波动率通常对应市场的温和上涨（贪婪）。",
-				Weight:      0.10,
-			},
-			{
-				ID:          "mfi",
-				Name:        "资金流量 (MFI)",
-				Description: "结合成交量的RSI，反映资金进出",
-				Detail:      "MFI 指标综合了价格和成交量来衡量买卖压力。相比普通的 RSI，MFI 往往能更早地发现顶背离和底背离信号。",
-				Weight:      0.15,
-			},
-			{
-				ID:          "bb_pct_b",
-				Name:        "布林带位置 (%B)",
-				Description: "价格在布林带中的相对位置",
-				Detail:      "布林带 %B 量化了当前价格相对于布林带上下轨的位置。%B > 1 表示股价突破上轨（贪婪/超买），%B < 0 表示跌破下轨（恐惧/超卖）。",
-				Weight:      0.15,
-			},
-		}
-		method = map[string]interface{}{
-			"reference_window_trading_days": window,
-			"normalize":                     "对每个子指标，计算其在参考周期内的滚动分位数，并映射为 0–100 分。",
-			"aggregate":                     "总分为可用子分数的加权平均：sum(score_i * w_i) / sum(w_i)。",
-		}
+	pf, err := provider.GetPrices(ctx, ticker, start, end, freq)
+	if err != nil {
+		return nil, err
 	}
-
-	resp := map[string]interface{}{
-		"ticker":           ticker,
-		"frequency":        freq,
-		"latest":           latest,
-		"series":           safeSeries,
-		"method":           method,
-		"components":       components,
-		"latest_subscores": latestSubscores,
+	if !end.IsZero() {
+		// Providers treat end loosely, make the window exact
+		pf = pf.Truncate(end)
 	}
-
-	// Set Cache
-	memCache.Set(cacheKey, resp, cache.DefaultExpiration)
-
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Cache", "MISS")
-	json.NewEncoder(w).Encode(resp)
+	return pf, nil
 }
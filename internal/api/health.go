@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Liveness only says the process is not wedged, a flaky provider must not get
+// the pod restarted. Readiness says it can serve scores right now: the
+// provider answers, the stores are writable and we are not shutting down.
+
+var (
+	draining  atomic.Bool
+	drainOnce sync.Once
+	drainCh   = make(chan struct{}) // closed by Drain
+
+	readyChecks []*readyCheck
+)
+
+type readyCheck struct {
+	name string
+	fn   func(context.Context) error
+
+	mu  sync.Mutex
+	at  time.Time
+	err error
+}
+
+// AddReadyCheck registers a dependency checked by /readyz. Call it before
+// Handler. Results are reused for health.probe_interval, so a probe every few
+// seconds does not turn into a provider request every few seconds.
+func AddReadyCheck(name string, fn func(context.Context) error) {
+	readyChecks = append(readyChecks, &readyCheck{name: name, fn: fn})
+}
+
+// ProviderCheck fetches the last days of ticker, which fails when the provider
+// is unreachable or answers with nothing.
+func ProviderCheck(p models.Provider, ticker string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		pf, err := p.GetPrices(ctx, ticker, time.Now().AddDate(0, 0, -7), time.Time{}, "1d")
+		if err != nil {
+			return err
+		}
+		if len(pf.Prices) == 0 {
+			return errors.New("no bars for " + ticker)
+		}
+		return nil
+	}
+}
+
+func (c *readyCheck) run(ctx context.Context) error {
+	// Holding the lock while checking means concurrent probes share one run
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.at.IsZero() && time.Since(c.at) < settings.Health.ProbeInterval.D() {
+		return c.err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	c.err = c.fn(ctx)
+	c.at = time.Now()
+	return c.err
+}
+
+// Drain marks the server as shutting down: /readyz fails so the load balancer
+// stops routing here, and open streams end so their clients reconnect to
+// another instance. Requests already running are left to finish.
+func Drain() {
+	drainOnce.Do(func() {
+		draining.Store(true)
+		close(drainCh)
+	})
+}
+
+func handleLivez(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, models.HealthResponse{Status: "ok"})
+}
+
+func handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if draining.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, models.HealthResponse{Status: "draining"})
+		return
+	}
+
+	resp := models.HealthResponse{Status: "ok", Checks: make(map[string]string, len(readyChecks))}
+	errs := make([]error, len(readyChecks))
+	var wg sync.WaitGroup
+	for i, c := range readyChecks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.run(r.Context())
+		}()
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	for i, c := range readyChecks {
+		resp.Checks[c.name] = "ok"
+		if errs[i] != nil {
+			resp.Checks[c.name] = errs[i].Error()
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, status, resp)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/archive"
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// archiveStore holds scores published by the scheduler. Nil means archiving is disabled.
+var archiveStore *archive.Store
+
+// SetArchive enables the /api/v1/fear-greed/history endpoint.
+func SetArchive(s *archive.Store) {
+	archiveStore = s
+}
+
+// historyParams are the parsed query of a history request.
+type historyParams struct {
+	Ticker, Freq, Lang string
+	Start, End         time.Time
+	Loc                *time.Location
+	// Raw values, for the ETag
+	StartStr, EndStr string
+}
+
+// loadHistory reads the archived snapshots for a history query. get is
+// usually url.Values.Get.
+func loadHistory(get func(string) string) (historyParams, []models.Snapshot, *apiError) {
+	if archiveStore == nil {
+		return historyParams{}, nil, &apiError{
+			Status: http.StatusServiceUnavailable,
+			Code:   models.ErrUnavailable,
+			Key:    "archive_disabled",
+		}
+	}
+
+	p := historyParams{Ticker: get("ticker"), Freq: get("freq"), Lang: get("lang"), StartStr: get("start"), EndStr: get("end")}
+	if p.Ticker == "" {
+		return p, nil, invalidParam("ticker_required")
+	}
+	if p.Freq == "" {
+		p.Freq = "1d"
+	}
+	p.Lang = langParam(p.Lang)
+
+	p.Loc = calendar.ExchangeFor(p.Ticker).Location
+	var err error
+	if p.Start, err = parseDay(p.StartStr, p.Loc); err != nil {
+		return p, nil, invalidParam("invalid_date", "start")
+	}
+	if p.End, err = parseDay(p.EndStr, p.Loc); err != nil {
+		return p, nil, invalidParam("invalid_date", "end")
+	}
+	if !p.End.IsZero() {
+		p.End = endOfDay(p.End)
+	}
+
+	snaps, err := archiveStore.History(p.Ticker, p.Freq, p.Start, p.End)
+	if err != nil {
+		return p, nil, internalError(err)
+	}
+	return p, snaps, nil
+}
+
+// historyResponse shapes snapshots, labeled in p.Lang.
+func historyResponse(p historyParams, snaps []models.Snapshot) models.HistoryResponse {
+	resp := models.HistoryResponse{
+		Ticker:    p.Ticker,
+		Frequency: p.Freq,
+		Series:    make([]models.PublishedScore, 0, len(snaps)),
+	}
+	for _, s := range snaps {
+		label, ok := s.Labels[p.Lang]
+		switch {
+		case ok:
+		case s.Band != "":
+			label = calc.BandLabel(s.Band, p.Lang)
+		default:
+			// Archived before bands were recorded
+			label = calc.LabelFromScore(s.Score, p.Lang)
+		}
+		resp.Series = append(resp.Series, models.PublishedScore{
+			Date:        calendar.FormatBarTime(s.Date, p.Freq, p.Loc),
+			Score:       s.Score,
+			Label:       label,
+			Price:       s.Price,
+			Subscores:   s.Subscores,
+			NormWindow:  s.NormWindow,
+			Bars:        s.Bars,
+			PublishedAt: s.PublishedAt,
+		})
+	}
+	return resp
+}
+
+// handleHistory serves the "as published" history: exactly what the scheduler
+// archived after each close, never recomputed.
+func handleHistory(w http.ResponseWriter, r *http.Request) {
+	p, snaps, apiErr := loadHistory(r.URL.Query().Get)
+	if apiErr != nil {
+		writeError(w, r, apiErr)
+		return
+	}
+
+	// Snapshots are write-once, a new one is the only change
+	var published time.Time
+	for _, s := range snaps {
+		if s.PublishedAt.After(published) {
+			published = s.PublishedAt
+		}
+	}
+	now := time.Now()
+	setCacheControl(w, r, p.Ticker, p.Freq, p.End, now)
+	etag := scoreETag(fmt.Sprint("history|", p.Ticker, "|", p.Freq, "|", p.StartStr, "|", p.EndStr, "|", p.Lang, "|", len(snaps), "|", published.UnixNano()), "", nil)
+	if notModified(w, r, etag, published) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, historyResponse(p, snaps))
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// scoreETag identifies one representation of a score series: the request
+// parameters, the output format and the newest bar. The last bar's price and
+// score are part of it, so a session in progress gets a new tag with every
+// tick while a closed one keeps its tag. Weak, because gzip and identity
+// encodings share it.
+func scoreETag(key, format string, results []models.ScoreResult) string {
+	h := fnv.New64a()
+	fmt.Fprint(h, key, "|", format, "|", len(results))
+	if n := len(results); n > 0 {
+		last := results[n-1]
+		fmt.Fprint(h, "|", last.Date.UnixNano(), "|", math.Float64bits(last.Price), "|", math.Float64bits(last.Score))
+	}
+	return fmt.Sprintf(`W/"%016x"`, h.Sum64())
+}
+
+// lastModified is when the newest bar became final, zero while it is still forming.
+func lastModified(results []models.ScoreResult, freq string, loc *time.Location, now time.Time) time.Time {
+	if len(results) == 0 {
+		return time.Time{}
+	}
+	end := barClose(results[len(results)-1].Date.In(loc), freq)
+	if end.After(now) {
+		return time.Time{}
+	}
+	return end
+}
+
+// notModified sets the validators and, when the client's copy matches them,
+// writes a 304 and returns true. If-None-Match wins over If-Modified-Since.
+func notModified(w http.ResponseWriter, r *http.Request, etag string, modified time.Time) bool {
+	h := w.Header()
+	h.Set("ETag", etag)
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagMatch(inm, etag) {
+			return false
+		}
+	} else if ims := r.Header.Get("If-Modified-Since"); ims != "" && !modified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err != nil || modified.Truncate(time.Second).After(t) {
+			return false
+		}
+	} else {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatch does the weak comparison of If-None-Match.
+func etagMatch(header, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// setCacheControl says how long a score response for ticker stays fresh.
+// until is the end of the requested range (end or asof), zero for "up to now".
+//
+// A range that is over only changes when a bar gets corrected, keep it a day.
+// While the market trades the last bar moves: intraday series are checked
+// every minute, daily ones follow the server cache. Once closed nothing
+// changes before the next open, but late prints and corrections trickle in,
+// so at most an hour.
+//
+// With API keys the response is private, a shared cache would hand it to
+// callers we never authenticated or charged.
+func setCacheControl(w http.ResponseWriter, r *http.Request, ticker, freq string, until, now time.Time) {
+	ex := calendar.ExchangeFor(ticker)
+	var age time.Duration
+	switch {
+	case !until.IsZero() && until.Before(now):
+		age = 24 * time.Hour
+	case ex.IsOpen(now) && freq == "1d":
+		age = settings.Cache.TTL.D()
+	case ex.IsOpen(now):
+		age = time.Minute
+	default:
+		age = min(ex.NextOpen(now).Sub(now), time.Hour)
+	}
+
+	scope := "public"
+	if settings.RateLimit.RequireKey || requestKey(r) != "" {
+		scope = "private"
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, int(age.Seconds())))
+}
+
+// rangeEnd is the earlier of end and asof that is set.
+func rangeEnd(end, asof time.Time) time.Time {
+	if end.IsZero() || (!asof.IsZero() && asof.Before(end)) {
+		return asof
+	}
+	return end
+}
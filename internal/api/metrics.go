@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/metrics"
+)
+
+var (
+	httpRequests = metrics.NewCounterVec("http_requests_total",
+		"HTTP requests by route pattern, method and status.", "route", "method", "status")
+	httpDuration = metrics.NewHistogramVec("http_request_duration_seconds",
+		"HTTP request latency by route pattern, method and status.", metrics.DefBuckets, "route", "method", "status")
+	grpcRequests = metrics.NewCounterVec("grpc_requests_total",
+		"gRPC calls by method and status code.", "method", "code")
+	grpcDuration = metrics.NewHistogramVec("grpc_request_duration_seconds",
+		"gRPC call latency by method and status code, streams until they end.", metrics.DefBuckets, "method", "code")
+	cacheRequests = metrics.NewCounterVec("score_cache_requests_total",
+		"Score cache lookups by result (hit or miss).", "result")
+	rateLimitRejections = metrics.NewCounterVec("rate_limit_rejections_total",
+		"Requests rejected with 429, by reason (rate or quota).", "reason")
+	computeDuration = metrics.NewHistogramVec("compute_duration_seconds",
+		"Time spent computing indicators and scores.", []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}, "freq")
+	computeBars = metrics.NewHistogramVec("compute_bars",
+		"Bars per score computation.", []float64{50, 100, 250, 500, 1000, 2500, 5000, 10000}, "freq")
+)
+
+func init() {
+	metrics.NewGaugeFunc("score_cache_items", "Entries in the cache, scores and bars.", func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		n, err := scoreCache.Len(ctx)
+		if err != nil {
+			return math.NaN()
+		}
+		return float64(n)
+	})
+	metrics.NewGaugeFunc("stream_feeds", "Tickers refreshed for live streams.", func() float64 {
+		if streamHub == nil {
+			return 0
+		}
+		return float64(streamHub.Feeds())
+	})
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+)
+
+// parseDay parses a YYYY-MM-DD query value as midnight in loc. Empty input
+// returns the zero time.
+func parseDay(v string, loc *time.Location) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", v, loc)
+}
+
+// endOfDay returns the last instant of t's day.
+func endOfDay(t time.Time) time.Time {
+	return t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// langParam is the locale of a lang parameter ("zh-Hant" is zh-TW), the
+// configured default when it is empty or unknown.
+func langParam(v string) string {
+	if lang, ok := i18n.Match(v); ok {
+		return lang
+	}
+	return settings.Score.Lang
+}
+
+// requestLang is the language of messages for r: the lang parameter, else
+// Accept-Language, else the configured default.
+func requestLang(r *http.Request) string {
+	if lang, ok := i18n.Match(r.URL.Query().Get("lang")); ok {
+		return lang
+	}
+	if lang, ok := i18n.Negotiate(r.Header.Get("Accept-Language")); ok {
+		return lang
+	}
+	return settings.Score.Lang
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// maxPointInTimeBars bounds the range of a point-in-time request. With a
+// revision-aware bar store every bar needs its own Compute.
+const maxPointInTimeBars = 1000
+
+// handlePointInTime returns, for every bar in [start, end], the score the model
+// would have shown on that bar using only data available then. Meant for
+// leakage-free training sets.
+func handlePointInTime(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	q := r.URL.Query()
+	ticker := q.Get("ticker")
+	if ticker == "" {
+		writeError(w, r, invalidParam("ticker_required"))
+		return
+	}
+	freq := q.Get("freq")
+	if freq == "" {
+		freq = "1d"
+	}
+	lang := langParam(q.Get("lang"))
+
+	loc := calendar.ExchangeFor(ticker).Location
+	start, err := parseDay(q.Get("start"), loc)
+	if err != nil || start.IsZero() {
+		writeError(w, r, invalidParam("invalid_date", "start"))
+		return
+	}
+	end, err := parseDay(q.Get("end"), loc)
+	if err != nil {
+		writeError(w, r, invalidParam("invalid_date", "end"))
+		return
+	}
+	if !end.IsZero() {
+		end = endOfDay(end)
+	}
+
+	cfg := calc.DefaultConfig
+	cfg.NormWindow = settings.Score.Window
+	if s := q.Get("window"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			writeError(w, r, invalidParam("invalid_positive", "window"))
+			return
+		}
+		cfg.NormWindow = v
+	}
+
+	slog.InfoContext(ctx, "point-in-time", "ticker", ticker, "start", q.Get("start"), "end", q.Get("end"), "freq", freq)
+
+	var results []models.ScoreResult
+	if ap, ok := provider.(models.AsOfProvider); ok {
+		h, err := ap.GetHistory(ctx, ticker, calc.WarmupStart(start, freq), end, freq)
+		if err != nil {
+			writeError(w, r, notFound("not_found", ticker+" ("+err.Error()+")"))
+			return
+		}
+
+		var bars []models.Price
+		for _, p := range h.AsOf(time.Time{}).Prices {
+			if !p.Date.Before(start) {
+				bars = append(bars, p)
+			}
+		}
+		if len(bars) > maxPointInTimeBars {
+			writeError(w, r, invalidParam("range_too_long", maxPointInTimeBars))
+			return
+		}
+
+		// Replay each bar with the revisions that were known once it closed
+		for _, p := range bars {
+			pf := h.AsOf(barClose(p.Date.In(loc), freq)).Truncate(p.Date)
+			part := calc.Compute(pf, cfg, lang)
+			if len(part) > 0 {
+				results = append(results, part[len(part)-1])
+			}
+		}
+	} else {
+		pf, err := provider.GetPrices(ctx, ticker, calc.WarmupStart(start, freq), end, freq)
+		if err != nil {
+			writeError(w, r, notFound("not_found", ticker+" ("+err.Error()+")"))
+			return
+		}
+		if !end.IsZero() {
+			pf = pf.Truncate(end)
+		}
+		results = calc.ComputePointInTime(pf, cfg, lang, start, end)
+	}
+
+	now := time.Now()
+	setCacheControl(w, r, ticker, freq, end, now)
+	key := fmt.Sprint("pit|", ticker, "|", freq, "|", q.Get("start"), "|", q.Get("end"), "|", lang, "|", cfg.NormWindow)
+	if notModified(w, r, scoreETag(key, "", results), lastModified(results, freq, loc, now)) {
+		return
+	}
+
+	resp := models.PointInTimeResponse{
+		Ticker:    ticker,
+		Frequency: freq,
+		Window:    cfg.NormWindow,
+		Series:    make([]models.PointInTimeScore, 0, len(results)),
+	}
+	for _, r := range results {
+		p := models.PointInTimeScore{
+			Date:      calendar.FormatBarTime(r.Date, freq, loc),
+			Label:     r.Label,
+			Price:     r.Price,
+			Subscores: make(map[string]*float64),
+		}
+		if !math.IsNaN(r.Score) {
+			v := r.Score
+			p.Score = &v
+		}
+		for k, v := range r.Subscores() {
+			if !math.IsNaN(v) {
+				v := v
+				p.Subscores[k] = &v
+			} else {
+				p.Subscores[k] = nil
+			}
+		}
+		resp.Series = append(resp.Series, p)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// barClose is when a bar starting at t is final: end of day for daily bars,
+// t plus the bar length for intraday ones.
+func barClose(t time.Time, freq string) time.Time {
+	if freq == "1d" {
+		y, m, d := t.Date()
+		return endOfDay(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+	}
+	step, err := time.ParseDuration(freq)
+	if err != nil {
+		step = time.Hour
+	}
+	return t.Add(step - time.Nanosecond)
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/barstore"
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/config"
+	"github.com/iwanlebron/stock-analysis/internal/data/fake"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// storeProvider serves bars straight from a bar store, revisions included.
+type storeProvider struct {
+	store *barstore.Store
+}
+
+func (p storeProvider) GetPrices(ctx context.Context, ticker string, start, end time.Time, freq string) (*models.PriceFrame, error) {
+	h, err := p.store.History(ticker, freq, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return h.AsOf(time.Time{}), nil
+}
+
+func (p storeProvider) GetHistory(ctx context.Context, ticker string, start, end time.Time, freq string) (models.BarHistory, error) {
+	return p.store.History(ticker, freq, start, end)
+}
+
+// A bar corrected after the fact is replayed in its first version on every
+// bar that closed before the correction was recorded.
+func TestPointInTimeReplaysRevisions(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	orig, err := (&fake.Provider{Now: func() time.Time { return now }}).GetPrices(context.Background(), "AAPL", now.AddDate(-1, 0, 0), now, "1d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bars := orig.Prices
+	k := len(bars) - 20
+	revised := &models.PriceFrame{Ticker: orig.Ticker, Frequency: orig.Frequency, Prices: append([]models.Price(nil), bars...)}
+	revised.Prices[k].Close *= 1.5
+	revised.Prices[k].High = revised.Prices[k].Close
+
+	ny := calendar.ExchangeFor("AAPL").Location
+	correctedAt := barClose(bars[k+5].Date.In(ny), "1d")
+	store, err := barstore.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Record(orig, bars[0].Date); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := store.Record(revised, correctedAt); n != 1 || err != nil {
+		t.Fatalf("recording the correction = %d, %v", n, err)
+	}
+
+	SetConfig(config.Default())
+	SetProvider(storeProvider{store})
+	Handler()
+
+	day := func(i int) string { return bars[i].Date.In(ny).Format(time.DateOnly) }
+	req := httptest.NewRequest("GET", "/api/v1/fear-greed/point-in-time?ticker=AAPL&window=60&start="+day(k-2)+"&end="+day(k+8), nil)
+	w := httptest.NewRecorder()
+	handlePointInTime(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var resp models.PointInTimeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Series) != 11 {
+		t.Fatalf("%d points, want bars %d to %d", len(resp.Series), k-2, k+8)
+	}
+
+	cfg := calc.DefaultConfig
+	cfg.NormWindow = 60
+	scoreOn := func(pf *models.PriceFrame, j int) float64 {
+		part := calc.Compute(&models.PriceFrame{Prices: pf.Prices[:j+1]}, cfg, "zh")
+		return part[j].Score
+	}
+	if scoreOn(orig, k) == scoreOn(revised, k) {
+		t.Fatal("the correction doesn't move the score, nothing to tell apart")
+	}
+	for i, p := range resp.Series {
+		j := k - 2 + i
+		src, known := orig, "first version"
+		if !barClose(bars[j].Date.In(ny), "1d").Before(correctedAt) {
+			src, known = revised, "correction"
+		}
+		if p.Price != src.Prices[j].Close {
+			t.Errorf("bar %d: price %g, want %g from the %s", j, p.Price, src.Prices[j].Close, known)
+		}
+		if want := scoreOn(src, j); p.Score == nil || *p.Score != want {
+			t.Errorf("bar %d: score %v, want %g from the %s", j, p.Score, want, known)
+		}
+	}
+}
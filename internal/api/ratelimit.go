@@ -0,0 +1,356 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/config"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Requests doing scoring work are charged to a token bucket per client, with
+// an optional daily quota on top. A client is an API key or, without one, the
+// caller's IP. Most requests cost one token, a batch one per ticker.
+
+var (
+	apiKeys        map[[32]byte]config.APIKey // by sha256 of the key, so lookups don't compare secrets
+	trustedProxies []netip.Prefix
+)
+
+// applyRateLimit indexes the keys and proxies of c, called by SetConfig.
+func applyRateLimit(c config.RateLimit) {
+	apiKeys = make(map[[32]byte]config.APIKey, len(c.Keys))
+	for _, k := range c.Keys {
+		apiKeys[sha256.Sum256([]byte(k.Key))] = k
+	}
+	// Validated when the config was loaded
+	trustedProxies, _ = config.ParsePrefixes(c.TrustedProxies)
+}
+
+// client is who a request is charged to.
+type client struct {
+	id        string // "key:<name>" or "ip:<addr>"
+	perMinute int    // refill rate, 0 unlimited
+	burst     int
+	daily     int // 0 unlimited
+}
+
+type bucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	day    string // UTC day counted in used
+	used   int
+}
+
+// limitState is what the RateLimit headers report: the limit closest to
+// running out, or the one that rejected the request.
+type limitState struct {
+	limit, remaining int
+	reset            time.Duration
+	retryAfter       time.Duration
+	reason           string // "rate" or "quota" when rejected
+}
+
+type clientKey struct{}
+
+// rateLimited reports whether a path does scoring work and counts against the limit.
+func rateLimited(path string) bool {
+	if path == "/api/v1/openapi.json" {
+		return false
+	}
+	return strings.HasPrefix(path, "/fear-greed") || strings.HasPrefix(path, "/api/v1/")
+}
+
+func rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !rateLimited(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		c, apiErr := identify(r)
+		if apiErr != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="stock-analysis"`)
+			writeError(w, r, apiErr)
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), clientKey{}, c))
+
+		// A batch is charged by its handler once it knows how many tickers it has
+		if r.URL.Path != "/api/v1/scores:batch" && !charge(w, r, 1) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// identify finds the client of r: the API key it presents, or its IP.
+func identify(r *http.Request) (client, *apiError) {
+	return identifyKey(requestKey(r), clientIP(r))
+}
+
+// identifyKey is the client presenting key, "" for none, from ip.
+func identifyKey(key, ip string) (client, *apiError) {
+	rl := settings.RateLimit
+	if key != "" {
+		k, ok := apiKeys[sha256.Sum256([]byte(key))]
+		if !ok {
+			return client{}, &apiError{Status: http.StatusUnauthorized, Code: models.ErrUnauthorized, Key: "api_key_invalid"}
+		}
+		c := client{id: "key:" + k.Name, perMinute: k.PerMinute, burst: k.Burst, daily: k.DailyQuota}
+		if c.perMinute == 0 {
+			c.perMinute = rl.KeyPerMinute
+		}
+		if c.daily == 0 {
+			c.daily = rl.KeyDailyQuota
+		}
+		if c.burst == 0 {
+			c.burst = c.perMinute
+		}
+		return c, nil
+	}
+	if rl.RequireKey {
+		return client{}, &apiError{Status: http.StatusUnauthorized, Code: models.ErrUnauthorized, Key: "api_key_missing"}
+	}
+	c := client{id: "ip:" + ip, perMinute: rl.PerMinute, burst: rl.Burst, daily: rl.DailyQuota}
+	if c.burst == 0 {
+		c.burst = c.perMinute
+	}
+	return c, nil
+}
+
+// requestKey is the key sent as X-API-Key, a bearer token or, for EventSource
+// which cannot set headers, the api_key query parameter.
+func requestKey(r *http.Request) string {
+	if k := r.Header.Get("X-API-Key"); k != "" {
+		return k
+	}
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return r.URL.Query().Get("api_key")
+}
+
+// clientIP is the peer address, unless the peer is a trusted proxy. Then the
+// X-Forwarded-For chain is walked from the right, where our own proxies
+// appended, and the first hop we don't trust is the client. Anything left of
+// it was written by the client and may be forged.
+func clientIP(r *http.Request) string {
+	return forwardedIP(r.RemoteAddr, r.Header.Values("X-Forwarded-For"))
+}
+
+// forwardedIP is clientIP for a peer address and its X-Forwarded-For values.
+func forwardedIP(remote string, xff []string) string {
+	host, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		host = remote
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !trusted(addr) {
+		return host
+	}
+	hops := strings.Split(strings.Join(xff, ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		a, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		addr = a.Unmap()
+		if !trusted(addr) {
+			break
+		}
+	}
+	return addr.String()
+}
+
+func trusted(a netip.Addr) bool {
+	a = a.Unmap()
+	for _, p := range trustedProxies {
+		if p.Contains(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// charge takes cost tokens from the client of r and sets the RateLimit
+// headers. When it returns false the error has been written.
+func charge(w http.ResponseWriter, r *http.Request, cost int) bool {
+	c, ok := r.Context().Value(clientKey{}).(client)
+	if !ok || c.unlimited() {
+		return true
+	}
+
+	st, apiErr := spend(c, cost)
+	h := w.Header()
+	if st.limit > 0 {
+		h.Set("RateLimit-Policy", c.policy())
+		h.Set("RateLimit-Limit", strconv.Itoa(st.limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(st.remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(seconds(st.reset)))
+	}
+	if apiErr == nil {
+		return true
+	}
+	if apiErr.Status == http.StatusTooManyRequests {
+		h.Set("Retry-After", strconv.Itoa(seconds(st.retryAfter)))
+	}
+	writeError(w, r, apiErr)
+	return false
+}
+
+func (c client) unlimited() bool {
+	return c.perMinute == 0 && c.daily == 0
+}
+
+// spend takes cost tokens from c. The state is for the RateLimit headers,
+// zero when the request was refused outright.
+func spend(c client, cost int) (limitState, *apiError) {
+	if c.perMinute > 0 && cost > c.burst {
+		// Would never fit, waiting does not help
+		return limitState{}, invalidParam("batch_over_burst", cost, c.burst)
+	}
+
+	st, ok := take(c, cost, time.Now())
+	if ok {
+		return st, nil
+	}
+	rateLimitRejections.Inc(st.reason)
+	key := "rate_limited"
+	if st.reason == "quota" {
+		key = "quota_exhausted"
+	}
+	return st, &apiError{Status: http.StatusTooManyRequests, Code: models.ErrRateLimited, Key: key}
+}
+
+// take charges cost to the bucket and daily count of c if both allow it.
+func take(c client, cost int, now time.Time) (limitState, bool) {
+	b := getBucket(c, now)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rate := float64(c.perMinute) / 60 // tokens per second
+	if c.perMinute > 0 {
+		b.tokens = math.Min(float64(c.burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	}
+	b.last = now
+	if day := now.UTC().Format("2006-01-02"); b.day != day {
+		b.day, b.used = day, 0
+	}
+
+	rateOK := c.perMinute == 0 || b.tokens >= float64(cost)
+	quotaOK := c.daily == 0 || b.used+cost <= c.daily
+	if rateOK && quotaOK {
+		if c.perMinute > 0 {
+			b.tokens -= float64(cost)
+		}
+		b.used += cost
+	}
+
+	var rs, qs limitState
+	if c.perMinute > 0 {
+		rs = limitState{
+			limit:      c.burst,
+			remaining:  int(b.tokens),
+			reset:      secondsDuration((float64(c.burst) - b.tokens) / rate),
+			retryAfter: secondsDuration((float64(cost) - b.tokens) / rate),
+			reason:     "rate",
+		}
+	}
+	if c.daily > 0 {
+		y, m, d := now.UTC().Date()
+		untilMidnight := time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC).Sub(now)
+		qs = limitState{
+			limit:      c.daily,
+			remaining:  max(c.daily-b.used, 0),
+			reset:      untilMidnight,
+			retryAfter: untilMidnight,
+			reason:     "quota",
+		}
+	}
+
+	switch {
+	case !quotaOK:
+		return qs, false
+	case !rateOK:
+		return rs, false
+	case c.perMinute == 0:
+		return qs, true
+	case c.daily == 0 || rs.remaining <= qs.remaining:
+		return rs, true
+	}
+	return qs, true
+}
+
+// getBucket returns the bucket of c, a full one for a new client. Buckets are
+// dropped once they would have refilled anyway, or with a daily quota once
+// the day is over.
+func getBucket(c client, now time.Time) *bucket {
+	ttl := time.Minute
+	if c.perMinute > 0 {
+		ttl += time.Duration(float64(c.burst) / float64(c.perMinute) * float64(time.Minute))
+	}
+	if c.daily > 0 {
+		y, m, d := now.UTC().Date()
+		ttl = time.Date(y, m, d+1, 0, 1, 0, 0, time.UTC).Sub(now)
+	}
+
+	key := "rl:" + c.id
+	b := &bucket{tokens: float64(c.burst), last: now}
+	if err := rateCache.Add(key, b, ttl); err != nil {
+		if v, ok := rateCache.Get(key); ok {
+			b = v.(*bucket)
+		}
+	}
+	// Extend the expiry on every use
+	rateCache.Set(key, b, ttl)
+	return b
+}
+
+// policy is the RateLimit-Policy header: the bucket as burst per refill window,
+// and the daily quota.
+func (c client) policy() string {
+	var parts []string
+	if c.perMinute > 0 {
+		window := int(math.Ceil(float64(c.burst) * 60 / float64(c.perMinute)))
+		parts = append(parts, fmt.Sprintf("%d;w=%d", c.burst, window))
+	}
+	if c.daily > 0 {
+		parts = append(parts, fmt.Sprintf("%d;w=86400", c.daily))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func secondsDuration(s float64) time.Duration {
+	if s < 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// redactedURL is u for logging, without the value of api_key.
+func redactedURL(u *url.URL) string {
+	q := u.Query()
+	if !q.Has("api_key") {
+		return u.String()
+	}
+	q.Set("api_key", "***")
+	v := *u
+	v.RawQuery = q.Encode()
+	return v.String()
+}
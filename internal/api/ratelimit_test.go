@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+	"github.com/iwanlebron/stock-analysis/internal/config"
+	"github.com/iwanlebron/stock-analysis/internal/data/fake"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+func TestForwardedIP(t *testing.T) {
+	defer applyRateLimit(settings.RateLimit)
+	applyRateLimit(config.RateLimit{TrustedProxies: []string{"10.0.0.0/8", "fd00::1"}})
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"untrusted peer", "203.0.113.5:4711", []string{"198.51.100.7"}, "203.0.113.5"},
+		{"untrusted peer without header", "203.0.113.5:4711", nil, "203.0.113.5"},
+		{"trusted peer without header", "10.0.0.1:4711", nil, "10.0.0.1"},
+		{"trusted peer with empty header", "10.0.0.1:4711", []string{""}, "10.0.0.1"},
+		{"one hop", "10.0.0.1:4711", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"spoofed leftmost", "10.0.0.1:4711", []string{"6.6.6.6, 198.51.100.7"}, "198.51.100.7"},
+		{"spoofed trusted leftmost", "10.0.0.1:4711", []string{"10.9.9.9, 198.51.100.7, 10.0.0.2"}, "198.51.100.7"},
+		{"two proxies", "10.0.0.1:4711", []string{"198.51.100.7, 10.0.0.2"}, "198.51.100.7"},
+		{"split over headers", "10.0.0.1:4711", []string{"6.6.6.6", "198.51.100.7 , 10.0.0.2"}, "198.51.100.7"},
+		{"all trusted", "10.0.0.1:4711", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		// Nothing left of a hop we can't read is believed
+		{"garbage hop", "10.0.0.1:4711", []string{"198.51.100.7, bogus, 10.0.0.2"}, "10.0.0.2"},
+		{"garbage last hop", "10.0.0.1:4711", []string{"198.51.100.7, bogus"}, "10.0.0.1"},
+		{"mapped IPv4", "10.0.0.1:4711", []string{"::ffff:198.51.100.7"}, "198.51.100.7"},
+		{"IPv6 proxy", "[fd00::1]:4711", []string{"2001:db8::7"}, "2001:db8::7"},
+		{"mapped proxy", "[::ffff:10.0.0.1]:4711", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"no port", "10.0.0.1", []string{"198.51.100.7"}, "198.51.100.7"},
+	}
+	for _, tt := range tests {
+		if got := forwardedIP(tt.remote, tt.xff); got != tt.want {
+			t.Errorf("%s: forwardedIP(%q, %q) = %s, want %s", tt.name, tt.remote, tt.xff, got, tt.want)
+		}
+	}
+}
+
+func TestTakeRate(t *testing.T) {
+	rateCache.Flush()
+	c := client{id: "ip:test-rate", perMinute: 60, burst: 3}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 2; i >= 0; i-- {
+		st, ok := take(c, 1, now)
+		if !ok || st.limit != 3 || st.remaining != i {
+			t.Fatalf("take %d: %+v, %v, want %d left", 3-i, st, ok, i)
+		}
+	}
+	st, ok := take(c, 1, now)
+	if ok || st.reason != "rate" || st.retryAfter != time.Second || st.reset != 3*time.Second {
+		t.Fatalf("empty bucket: %+v, %v, want refused for a second", st, ok)
+	}
+	if _, ok := take(c, 2, now.Add(time.Second)); ok {
+		t.Error("took 2 tokens after refilling 1")
+	}
+	if st, ok := take(c, 1, now.Add(time.Second)); !ok || st.remaining != 0 {
+		t.Errorf("after a second: %+v, %v, want the refilled token", st, ok)
+	}
+	// Refills up to the burst, not beyond
+	if st, _ := take(c, 1, now.Add(time.Hour)); st.remaining != 2 {
+		t.Errorf("after an hour: %d left, want burst 3 minus 1", st.remaining)
+	}
+}
+
+func TestTakeQuota(t *testing.T) {
+	rateCache.Flush()
+	c := client{id: "ip:test-quota", perMinute: 600, burst: 10, daily: 3}
+	now := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+
+	st, ok := take(c, 2, now)
+	// The quota is closer to running out than the bucket
+	if !ok || st.reason != "quota" || st.remaining != 1 || st.reset != time.Hour {
+		t.Fatalf("first take: %+v, %v", st, ok)
+	}
+	st, ok = take(c, 2, now)
+	if ok || st.reason != "quota" || st.retryAfter != time.Hour {
+		t.Fatalf("over quota: %+v, %v, want refused until midnight", st, ok)
+	}
+	if _, ok := take(c, 1, now); !ok {
+		t.Error("the last token of the day was refused")
+	}
+	if st, ok := take(c, 3, now.Add(time.Hour)); !ok || st.remaining != 0 {
+		t.Errorf("next day: %+v, %v, want a fresh quota", st, ok)
+	}
+}
+
+// A batch costs a token per ticker, charged to the same bucket as single
+// requests, and forged X-Forwarded-For headers don't buy a fresh one.
+func TestBatchCharge(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimit.PerMinute = 1
+	cfg.RateLimit.Burst = 5
+	cfg.RateLimit.KeyPerMinute = 10
+	cfg.RateLimit.Keys = config.APIKeys{{Name: "ci", Key: "s3cret"}}
+	SetConfig(cfg)
+	defer SetConfig(config.Default())
+	SetProvider(&fake.Provider{})
+	SetCache(cache.NewMemory(time.Minute))
+	h := Handler()
+
+	do := func(method, target, body string, header ...string) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		for i := 0; i+1 < len(header); i += 2 {
+			r.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	batch := func(n int, header ...string) *httptest.ResponseRecorder {
+		return do("POST", "/api/v1/scores:batch", `{"tickers":["`+strings.Repeat(`AAPL","`, n-1)+`AAPL"]}`, header...)
+	}
+
+	steps := []struct {
+		name      string
+		w         func() *httptest.ResponseRecorder
+		status    int
+		remaining string
+		code      string
+	}{
+		{"batch of 3", func() *httptest.ResponseRecorder { return batch(3) }, http.StatusOK, "2", ""},
+		{"single", func() *httptest.ResponseRecorder { return do("GET", "/api/v1/fear-greed?ticker=AAPL", "") }, http.StatusOK, "1", ""},
+		{"batch of 2", func() *httptest.ResponseRecorder { return batch(2) }, http.StatusTooManyRequests, "1", models.ErrRateLimited},
+		{"forged client", func() *httptest.ResponseRecorder { return batch(2, "X-Forwarded-For", "6.6.6.6") }, http.StatusTooManyRequests, "1", models.ErrRateLimited},
+		{"over burst", func() *httptest.ResponseRecorder { return batch(6) }, http.StatusBadRequest, "", models.ErrInvalidParameter},
+		{"key has its own bucket", func() *httptest.ResponseRecorder { return batch(2, "X-API-Key", "s3cret") }, http.StatusOK, "8", ""},
+		{"unknown key", func() *httptest.ResponseRecorder { return batch(1, "X-API-Key", "guess") }, http.StatusUnauthorized, "", models.ErrUnauthorized},
+	}
+	for _, st := range steps {
+		w := st.w()
+		if w.Code != st.status {
+			t.Fatalf("%s: status %d, want %d: %s", st.name, w.Code, st.status, w.Body)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != st.remaining {
+			t.Errorf("%s: RateLimit-Remaining %q, want %q", st.name, got, st.remaining)
+		}
+		if st.code != "" && !strings.Contains(w.Body.String(), `"`+st.code+`"`) {
+			t.Errorf("%s: body %s, want code %s", st.name, w.Body, st.code)
+		}
+		if st.status == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Errorf("%s: no Retry-After", st.name)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// apiError is a failure that is reported to the client in the error envelope.
+// The message is a catalog key, localized when written.
+type apiError struct {
+	Status int
+	Code   string
+	Key    string // under "error." in the catalog
+	Args   []any
+}
+
+func (e *apiError) Error() string {
+	return e.Message(settings.Score.Lang)
+}
+
+// Message is the error text in lang.
+func (e *apiError) Message(lang string) string {
+	return i18n.T(lang, "error."+e.Key, e.Args...)
+}
+
+func invalidParam(key string, args ...any) *apiError {
+	return &apiError{Status: http.StatusBadRequest, Code: models.ErrInvalidParameter, Key: key, Args: args}
+}
+
+func notFound(key string, args ...any) *apiError {
+	return &apiError{Status: http.StatusNotFound, Code: models.ErrNotFound, Key: key, Args: args}
+}
+
+func internalError(err error) *apiError {
+	return &apiError{Status: http.StatusInternalServerError, Code: models.ErrInternal, Key: "internal", Args: []any{err.Error()}}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError sends err in the language of r, see requestLang.
+func writeError(w http.ResponseWriter, r *http.Request, err *apiError) {
+	// Never let a browser or CDN keep a 404 or 429 around
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, err.Status, models.ErrorResponse{
+		Error: models.ErrorBody{Code: err.Code, Message: err.Message(requestLang(r))},
+	})
+}
@@ -0,0 +1,404 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/metrics"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// scoreParams are the validated inputs of a score request.
+type scoreParams struct {
+	Ticker string
+	Freq   string
+	Lang   string
+	Labels string // label scheme id
+	Smooth calc.Smoothing
+	Window int
+	Tail   int
+
+	// Indicators checked for divergence from price, none by default
+	Divergence []string
+	Pivot      int
+
+	// Timeframes blended into a fused score, none by default
+	Timeframes []calc.TimeframeWeight
+
+	// Exchange-local bounds; zero means open
+	Start, End, Asof time.Time
+	Loc              *time.Location
+
+	// Raw values, for logging and the cache key
+	StartStr, EndStr, AsofStr string
+}
+
+func (p scoreParams) cacheKey() string {
+	return fmt.Sprintf("%s-%s-%s-%s-%s-%d-%d-%s-%s-%s-%d-%s", p.Ticker, p.Freq, p.StartStr, p.EndStr, p.Lang, p.Window, p.Tail, p.AsofStr, p.Labels, p.Smooth.Method, p.Smooth.Period, calc.FormatTimeframes(p.Timeframes))
+}
+
+// calcConfig is the calc config the request asks for.
+func (p scoreParams) calcConfig() calc.Config {
+	cfg := calc.DefaultConfig
+	cfg.NormWindow = p.Window
+	cfg.Labels = p.Labels
+	cfg.Smoothing = p.Smooth
+	return cfg
+}
+
+// defaultCalcConfig is the calc config of a request leaving everything to
+// the settings.
+func defaultCalcConfig() calc.Config {
+	return scoreParams{
+		Labels: settings.Score.Labels,
+		Smooth: calc.Smoothing{Method: settings.Score.Smooth, Period: settings.Score.SmoothPeriod},
+		Window: settings.Score.Window,
+	}.calcConfig()
+}
+
+// parseScoreParams validates request parameters. get is usually url.Values.Get.
+func parseScoreParams(get func(string) string) (scoreParams, *apiError) {
+	p := scoreParams{
+		Ticker: get("ticker"),
+		Freq:   get("freq"),
+		Lang:   get("lang"),
+		Labels: get("labels"),
+		Smooth: calc.Smoothing{Method: settings.Score.Smooth, Period: settings.Score.SmoothPeriod},
+		Window: settings.Score.Window,
+		Tail:   settings.Score.Tail,
+	}
+	if p.Ticker == "" {
+		return p, invalidParam("ticker_required")
+	}
+	if p.Freq == "" {
+		p.Freq = "1d"
+	}
+	p.Lang = langParam(p.Lang)
+	if p.Labels == "" {
+		p.Labels = settings.Score.Labels
+	}
+	if _, ok := calc.LookupScheme(p.Labels); !ok {
+		return p, invalidParam("invalid_labels", strings.Join(calc.SchemeIDs(), ", "))
+	}
+
+	// Dates are exchange-local days: start=2024-03-01 means from that day's open
+	// in New York for AAPL, in Hong Kong for 0700.HK
+	p.Loc = calendar.ExchangeFor(p.Ticker).Location
+
+	var err error
+	p.StartStr = get("start")
+	if p.Start, err = parseDay(p.StartStr, p.Loc); err != nil {
+		return p, invalidParam("invalid_date", "start")
+	}
+
+	p.EndStr = get("end")
+	if p.End, err = parseDay(p.EndStr, p.Loc); err != nil {
+		return p, invalidParam("invalid_date", "end")
+	}
+	if !p.End.IsZero() {
+		p.End = endOfDay(p.End)
+		if !p.Start.IsZero() && p.End.Before(p.Start) {
+			return p, invalidParam("end_before_start")
+		}
+	}
+
+	// asof: only use bars (and bar revisions) that existed on that day
+	p.AsofStr = get("asof")
+	if p.Asof, err = parseDay(p.AsofStr, p.Loc); err != nil {
+		return p, invalidParam("invalid_date", "asof")
+	}
+	if !p.Asof.IsZero() {
+		p.Asof = endOfDay(p.Asof)
+	}
+
+	if s := get("window"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			return p, invalidParam("invalid_positive", "window")
+		}
+		p.Window = v
+	}
+
+	if s := get("tail"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			return p, invalidParam("invalid_positive", "tail")
+		}
+		p.Tail = v
+	}
+
+	if s := get("smooth"); s != "" {
+		p.Smooth.Method = strings.ToLower(s)
+		if p.Smooth.Validate() != nil {
+			return p, invalidParam("invalid_smooth")
+		}
+	}
+	if s := get("smooth_period"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			return p, invalidParam("invalid_positive", "smooth_period")
+		}
+		p.Smooth.Period = v
+	}
+	if s := get("divergence"); s != "" {
+		for _, id := range strings.Split(s, ",") {
+			if id = strings.TrimSpace(id); !calc.IsIndicator(id) {
+				return p, invalidParam("invalid_divergence", strings.Join(append([]string{"score"}, calc.Components...), ", "))
+			}
+			p.Divergence = append(p.Divergence, id)
+		}
+	}
+	p.Pivot = calc.DefaultDivergence.Pivot
+	if s := get("pivot"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			return p, invalidParam("invalid_positive", "pivot")
+		}
+		p.Pivot = v
+	}
+	// mtf=true blends the configured timeframes, timeframes= picks them
+	if s := get("timeframes"); s != "" {
+		tfs, err := calc.ParseTimeframes(s)
+		if err != nil {
+			return p, invalidParam("invalid_timeframes")
+		}
+		p.Timeframes = tfs
+	} else if get("mtf") == "true" {
+		p.Timeframes, _ = calc.ParseTimeframes(settings.Score.Timeframes)
+	}
+
+	if !p.Smooth.Enabled() {
+		// Off: the period doesn't matter, keep it out of the cache key
+		p.Smooth = calc.Smoothing{Method: calc.SmoothNone}
+	}
+
+	return p, nil
+}
+
+// computeResults fetches and computes the full result series for p, including
+// the warmup bars, going through scoreCache. hit reports a cache hit.
+func computeResults(ctx context.Context, p scoreParams) (results []models.ScoreResult, hit bool, apiErr *apiError) {
+	results, hit, err := cache.Fetch(ctx, scoreCache, cache.Key("score", p.Ticker, p.cacheKey()), settings.Cache.TTL.D(), func(ctx context.Context) ([]models.ScoreResult, error) {
+		cacheRequests.Inc("miss")
+		results, apiErr := computeUncached(ctx, p)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		return results, nil
+	})
+	if err != nil {
+		if errors.As(err, &apiErr) {
+			return nil, false, apiErr
+		}
+		if ctx.Err() != nil {
+			// Gave up waiting, as a fetch running out of time would have
+			return nil, false, notFound("not_found", p.Ticker+" ("+err.Error()+")")
+		}
+		return nil, false, internalError(err)
+	}
+	if hit {
+		cacheRequests.Inc("hit")
+	}
+	return results, hit, nil
+}
+
+func computeUncached(ctx context.Context, p scoreParams) ([]models.ScoreResult, *apiError) {
+	// Adjust start date to fetch earlier data for warmup (e.g. MA60 needs 60 bars)
+	// We add buffer based on window.
+	// To be safe, we fetch (window * 2) extra days.
+	// For daily: 252 * 2 = ~500 days (approx 2 years)
+	fetchStart := calc.WarmupStart(p.Start, p.Freq)
+
+	// Fetch Data
+	slog.InfoContext(ctx, "fetching data", "ticker", p.Ticker, "start", p.StartStr, "end", p.EndStr, "asof", p.AsofStr, "freq", p.Freq)
+	pf, err := fetchPrices(ctx, p.Ticker, fetchStart, p.End, p.Freq, p.Asof)
+	if err != nil {
+		slog.WarnContext(ctx, "fetch failed", "ticker", p.Ticker, "err", err)
+		return nil, notFound("not_found", p.Ticker+" ("+err.Error()+")")
+	}
+	if len(pf.Prices) == 0 {
+		return nil, notFound("not_found", p.Ticker)
+	}
+
+	// Compute
+	cfg := p.calcConfig()
+	t0 := time.Now()
+	results := calc.Compute(pf, cfg, p.Lang)
+	elapsed := time.Since(t0)
+	slog.InfoContext(ctx, "computed indicators", "ticker", p.Ticker, "bars", len(pf.Prices), "duration_ms", float64(elapsed.Microseconds())/1000)
+	computeDuration.Observe(elapsed.Seconds(), metrics.FreqLabel(p.Freq))
+	computeBars.Observe(float64(len(pf.Prices)), metrics.FreqLabel(p.Freq))
+	if len(p.Timeframes) > 0 {
+		fuse(ctx, p, results)
+	}
+	return results, nil
+}
+
+// visible cuts the warmup bars off results, leaving the requested window.
+func visible(p scoreParams, results []models.ScoreResult) []models.ScoreResult {
+	// Find start index based on user request "start" time
+	// We calculated results starting from fetchStart (which is start - buffer).
+	// Now we want to find the index that corresponds to the user's requested 'start'.
+	// fetchPrices already cut everything after end, so an explicit range is exactly
+	// [start, end] and may well be empty.
+	startIdx := len(results)
+	if !p.Start.IsZero() {
+		for i, r := range results {
+			// Find the first date >= user requested start
+			if !r.Date.Before(p.Start) {
+				startIdx = i
+				break
+			}
+		}
+	} else {
+		// If no start provided, default logic (e.g. tail)
+		startIdx = len(results) - p.Tail
+		if startIdx < 0 {
+			startIdx = 0
+		}
+	}
+	return results[startIdx:]
+}
+
+// buildResponse cuts results to the requested window and shapes the API response.
+func buildResponse(p scoreParams, results []models.ScoreResult) *models.APIResponse {
+	// NaN can't be encoded in JSON, scores that aren't available yet become null
+	window := visible(p, results)
+	series := make([]models.SeriesPoint, 0, len(window))
+	for _, r := range window {
+		var s *float64
+		if !math.IsNaN(r.Score) {
+			v := r.Score
+			s = &v
+		}
+		series = append(series, models.SeriesPoint{
+			Date:   calendar.FormatBarTime(r.Date, p.Freq, p.Loc),
+			Score:  s,
+			Label:  r.Label,
+			Band:   r.Band,
+			Regime: r.Regime,
+			Price:  r.Price,
+		})
+		pt := &series[len(series)-1]
+		if s != nil {
+			pt.Quality = &r.Quality
+		}
+		if p.Smooth.Enabled() {
+			pt.Signal = optional(r.Signal)
+			pt.Event = r.Cross
+		}
+		if r.Timeframes != nil {
+			pt.Fused, pt.Timeframes = optional(r.Fused), timeframeScores(r)
+		}
+	}
+
+	resp := &models.APIResponse{
+		Ticker:    p.Ticker,
+		Frequency: p.Freq,
+		Series:    series,
+	}
+
+	// Latest
+	if len(results) > 0 {
+		last := results[len(results)-1]
+		if !math.IsNaN(last.Score) {
+			resp.Latest = &models.SimpleScore{
+				Date:   calendar.FormatBarTime(last.Date, p.Freq, p.Loc),
+				Score:  last.Score,
+				Label:  last.Label,
+				Band:   last.Band,
+				Regime: last.Regime,
+				Price:  last.Price,
+			}
+			if p.Smooth.Enabled() {
+				resp.Latest.Signal = optional(last.Signal)
+			}
+			resp.Latest.Quality = &last.Quality
+			if last.Timeframes != nil {
+				resp.Latest.Fused, resp.Latest.Timeframes = optional(last.Fused), timeframeScores(last)
+			}
+			resp.LatestSubscores = make(map[string]float64)
+			for k, v := range last.Subscores() {
+				if !math.IsNaN(v) {
+					resp.LatestSubscores[k] = v
+				}
+			}
+		}
+	}
+
+	resp.Components, resp.Method = describe(p.Lang, p.Window)
+	scheme, _ := calc.LookupScheme(p.Labels)
+	resp.LabelScheme = calc.DescribeScheme(scheme, p.Lang)
+	if p.Smooth.Enabled() {
+		resp.Smoothing = &models.SmoothingInfo{Method: p.Smooth.Method, Period: p.Smooth.Period}
+	}
+	resp.Divergences = divergences(p, results, len(results)-len(window))
+	resp.Regimes = regimes(p, window)
+	if len(p.Timeframes) > 0 {
+		resp.Fusion = fusionInfo(p.Timeframes)
+	}
+	return resp
+}
+
+// regimes is the regime history of window, oldest first. The first span is
+// cut at the window start.
+func regimes(p scoreParams, window []models.ScoreResult) []models.RegimeSpan {
+	out := []models.RegimeSpan{}
+	for _, sp := range calc.RegimeSpans(window) {
+		out = append(out, models.RegimeSpan{
+			Regime: sp.Regime,
+			Name:   calc.RegimeName(sp.Regime, p.Lang),
+			Start:  calendar.FormatBarTime(window[sp.Start].Date, p.Freq, p.Loc),
+			End:    calendar.FormatBarTime(window[sp.End].Date, p.Freq, p.Loc),
+			Bars:   sp.End - sp.Start + 1,
+		})
+	}
+	return out
+}
+
+// divergences finds the divergences of p.Divergence over all of results,
+// keeping the ones that end at or after bar from.
+func divergences(p scoreParams, results []models.ScoreResult, from int) []models.Divergence {
+	var out []models.Divergence
+	cfg := calc.DefaultDivergence
+	cfg.Pivot = p.Pivot
+	for _, id := range p.Divergence {
+		for _, d := range calc.Divergences(results, id, cfg) {
+			if d.End < from {
+				continue
+			}
+			a, b := results[d.Start], results[d.End]
+			out = append(out, models.Divergence{
+				Kind:        d.Kind,
+				Indicator:   id,
+				Start:       calendar.FormatBarTime(a.Date, p.Freq, p.Loc),
+				End:         calendar.FormatBarTime(b.Date, p.Freq, p.Loc),
+				Confirmed:   calendar.FormatBarTime(results[d.Confirmed].Date, p.Freq, p.Loc),
+				StartPrice:  a.Price,
+				EndPrice:    b.Price,
+				StartValue:  calc.IndicatorValue(a, id),
+				EndValue:    calc.IndicatorValue(b, id),
+				PriceChange: d.PriceChange,
+				Strength:    d.Strength,
+			})
+		}
+	}
+	return out
+}
+
+// optional is v, nil for NaN which JSON can't encode.
+func optional(v float64) *float64 {
+	if math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+	"github.com/iwanlebron/stock-analysis/internal/stream"
+)
+
+const (
+	maxStreamTickers = 20
+	heartbeatEvery   = 15 * time.Second
+)
+
+// streamHub refreshes the tickers watched over /api/v1/stream. Handler creates
+// it on the current provider.
+var streamHub *stream.Hub
+
+// handleStream pushes the latest score of each ticker as Server-Sent Events.
+// Every connection shares the hub's refresh loops, so viewers are cheap.
+func handleStream(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	tickers, freq, lang, apiErr := parseWatch(strings.Split(q.Get("tickers"), ","), q.Get("freq"), q.Get("lang"))
+	if apiErr != nil {
+		writeError(w, r, apiErr)
+		return
+	}
+
+	// The connection stays open, lift the server-wide write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_ = rc.Flush()
+
+	updates := subscribe(r.Context(), tickers, freq)
+
+	heartbeat := time.NewTicker(heartbeatEvery)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-drainCh:
+			// Shutting down, the browser reconnects to another instance
+			return
+		case <-heartbeat.C:
+			// Comment line, keeps proxies from closing an idle connection
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case u := <-updates:
+			data, err := json.Marshal(streamEvent(u, lang))
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: score\ndata: %s\n\n", data); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// parseWatch validates the tickers, freq and lang of a live stream.
+func parseWatch(list []string, freq, lang string) ([]string, string, string, *apiError) {
+	var tickers []string
+	seen := map[string]bool{}
+	for _, t := range list {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			tickers = append(tickers, t)
+		}
+	}
+	if len(tickers) == 0 {
+		return nil, "", "", invalidParam("tickers_required")
+	}
+	if len(tickers) > maxStreamTickers {
+		return nil, "", "", invalidParam("too_many_tickers", maxStreamTickers)
+	}
+	if freq == "" {
+		freq = "1d"
+	}
+	if freq != "1d" && freq != "1h" {
+		return nil, "", "", invalidParam("invalid_freq")
+	}
+	return tickers, freq, langParam(lang), nil
+}
+
+// subscribe merges the hub updates of tickers into one channel until ctx is
+// done.
+func subscribe(ctx context.Context, tickers []string, freq string) <-chan stream.Update {
+	updates := make(chan stream.Update)
+	for _, t := range tickers {
+		ch, unsubscribe := streamHub.Subscribe(t, freq)
+		go func() {
+			defer unsubscribe()
+			for {
+				select {
+				case u := <-ch:
+					select {
+					case updates <- u:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	return updates
+}
+
+func streamEvent(u stream.Update, lang string) models.StreamEvent {
+	r := u.Result
+	ev := models.StreamEvent{
+		Ticker:    u.Ticker,
+		Frequency: u.Freq,
+		Date:      calendar.FormatBarTime(r.Date, u.Freq, calendar.ExchangeFor(u.Ticker).Location),
+		Label:     calc.BandLabel(r.Band, lang),
+		Band:      r.Band,
+		Regime:    r.Regime,
+		Price:     r.Price,
+	}
+	if !math.IsNaN(r.Score) {
+		v := r.Score
+		ev.Score = &v
+		ev.Confidence = r.Quality.Confidence
+		ev.Subscores = make(map[string]float64)
+		for k, v := range r.Subscores() {
+			if !math.IsNaN(v) {
+				ev.Subscores[k] = v
+			}
+		}
+	}
+	return ev
+}
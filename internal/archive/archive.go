@@ -0,0 +1,174 @@
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Store persists published snapshots as one JSON-lines file per ticker/frequency.
+// Snapshots are write-once: the first score published for a bar is the one we keep,
+// later recomputations never overwrite it.
+type Store struct {
+	dir string
+
+	mu     sync.Mutex
+	loaded map[string][]models.Snapshot // file key -> snapshots sorted by date
+}
+
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("archive: create %s: %w", dir, err)
+	}
+	return &Store{dir: dir, loaded: make(map[string][]models.Snapshot)}, nil
+}
+
+// Check reports whether the store directory is still writable, e.g. the volume
+// is mounted and not full. Used by the readiness probe.
+func (s *Store) Check() error {
+	f, err := os.CreateTemp(s.dir, ".probe-*")
+	if err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	name := f.Name()
+	_, err = f.WriteString("ok")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	os.Remove(name)
+	if err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	return nil
+}
+
+func fileKey(ticker, freq string) string {
+	// Tickers contain things like ^HSI or 0700.HK, escape so they are safe file names
+	return url.PathEscape(strings.ToUpper(ticker)) + "_" + url.PathEscape(freq)
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+".jsonl")
+}
+
+// load reads a ticker file into memory. Caller holds s.mu.
+func (s *Store) load(key string) ([]models.Snapshot, error) {
+	if snaps, ok := s.loaded[key]; ok {
+		return snaps, nil
+	}
+
+	f, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		s.loaded[key] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var snaps []models.Snapshot
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var snap models.Snapshot
+		if err := json.Unmarshal(line, &snap); err != nil {
+			return nil, fmt.Errorf("archive: corrupt line in %s: %w", s.path(key), err)
+		}
+		snaps = append(snaps, snap)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(snaps, func(i, j int) bool { return snaps[i].Date.Before(snaps[j].Date) })
+	s.loaded[key] = snaps
+	return snaps, nil
+}
+
+// Has reports whether a snapshot for this bar was already published.
+func (s *Store) Has(ticker, freq string, date time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snaps, err := s.load(fileKey(ticker, freq))
+	if err != nil {
+		return false, err
+	}
+	i := sort.Search(len(snaps), func(i int) bool { return !snaps[i].Date.Before(date) })
+	return i < len(snaps) && snaps[i].Date.Equal(date), nil
+}
+
+// Append publishes a snapshot. It returns false if the bar was already archived.
+func (s *Store) Append(snap models.Snapshot) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fileKey(snap.Ticker, snap.Frequency)
+	snaps, err := s.load(key)
+	if err != nil {
+		return false, err
+	}
+	i := sort.Search(len(snaps), func(i int) bool { return !snaps[i].Date.Before(snap.Date) })
+	if i < len(snaps) && snaps[i].Date.Equal(snap.Date) {
+		return false, nil
+	}
+
+	line, err := json.Marshal(snap)
+	if err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(s.path(key), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return false, err
+	}
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+
+	snaps = append(snaps, models.Snapshot{})
+	copy(snaps[i+1:], snaps[i:])
+	snaps[i] = snap
+	s.loaded[key] = snaps
+	return true, nil
+}
+
+// History returns archived snapshots with start <= date <= end. Zero bounds are open.
+func (s *Store) History(ticker, freq string, start, end time.Time) ([]models.Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snaps, err := s.load(fileKey(ticker, freq))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.Snapshot, 0, len(snaps))
+	for _, snap := range snaps {
+		if !start.IsZero() && snap.Date.Before(start) {
+			continue
+		}
+		if !end.IsZero() && snap.Date.After(end) {
+			continue
+		}
+		out = append(out, snap)
+	}
+	return out, nil
+}
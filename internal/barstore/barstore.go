@@ -0,0 +1,244 @@
+package barstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// revision is one version of a bar as the provider reported it at RecordedAt.
+// Providers revise bars (splits, late prints, the still-forming last candle),
+// so we keep every version instead of overwriting.
+type revision struct {
+	Bar        models.Price `json:"bar"`
+	RecordedAt time.Time    `json:"recorded_at"`
+}
+
+type series struct {
+	revs map[int64][]revision // bar unix time -> revisions, oldest first
+}
+
+// Store is a bitemporal bar store backed by one JSON-lines file per ticker/frequency.
+type Store struct {
+	dir string
+
+	mu     sync.Mutex
+	loaded map[string]*series
+}
+
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("barstore: create %s: %w", dir, err)
+	}
+	return &Store{dir: dir, loaded: make(map[string]*series)}, nil
+}
+
+// Check reports whether the store directory is still writable, e.g. the volume
+// is mounted and not full. Used by the readiness probe.
+func (s *Store) Check() error {
+	f, err := os.CreateTemp(s.dir, ".probe-*")
+	if err != nil {
+		return fmt.Errorf("barstore: %w", err)
+	}
+	name := f.Name()
+	_, err = f.WriteString("ok")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	os.Remove(name)
+	if err != nil {
+		return fmt.Errorf("barstore: %w", err)
+	}
+	return nil
+}
+
+func fileKey(ticker, freq string) string {
+	return url.PathEscape(strings.ToUpper(ticker)) + "_" + url.PathEscape(freq)
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+".jsonl")
+}
+
+// load reads a ticker file into memory. Caller holds s.mu.
+func (s *Store) load(key string) (*series, error) {
+	if sr, ok := s.loaded[key]; ok {
+		return sr, nil
+	}
+
+	sr := &series{revs: make(map[int64][]revision)}
+	f, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		s.loaded[key] = sr
+		return sr, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rev revision
+		if err := json.Unmarshal(line, &rev); err != nil {
+			return nil, fmt.Errorf("barstore: corrupt line in %s: %w", s.path(key), err)
+		}
+		ts := rev.Bar.Date.Unix()
+		sr.revs[ts] = append(sr.revs[ts], rev)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	s.loaded[key] = sr
+	return sr, nil
+}
+
+func sameBar(a, b models.Price) bool {
+	return a.Open == b.Open && a.High == b.High && a.Low == b.Low && a.Close == b.Close && a.Volume == b.Volume
+}
+
+// Record stores every bar of pf that is new or differs from its latest known
+// revision. It returns the number of revisions written.
+func (s *Store) Record(pf *models.PriceFrame, at time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fileKey(pf.Ticker, pf.Frequency)
+	sr, err := s.load(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var buf []byte
+	var added []revision
+	for _, p := range pf.Prices {
+		revs := sr.revs[p.Date.Unix()]
+		if len(revs) > 0 && sameBar(revs[len(revs)-1].Bar, p) {
+			continue
+		}
+		rev := revision{Bar: p, RecordedAt: at.UTC()}
+		line, err := json.Marshal(rev)
+		if err != nil {
+			return 0, err
+		}
+		buf = append(append(buf, line...), '\n')
+		added = append(added, rev)
+	}
+	if len(added) == 0 {
+		return 0, nil
+	}
+
+	f, err := os.OpenFile(s.path(key), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+
+	for _, rev := range added {
+		ts := rev.Bar.Date.Unix()
+		sr.revs[ts] = append(sr.revs[ts], rev)
+	}
+	return len(added), nil
+}
+
+// History is an immutable copy of the revisions of a date range.
+type History struct {
+	ticker, freq string
+	bars         [][]revision // per bar, sorted by bar date; revisions oldest first
+}
+
+// History copies the revisions of bars with start <= date <= end. Zero bounds are open.
+func (s *Store) History(ticker, freq string, start, end time.Time) (*History, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sr, err := s.load(fileKey(ticker, freq))
+	if err != nil {
+		return nil, err
+	}
+
+	h := &History{ticker: ticker, freq: freq}
+	for _, revs := range sr.revs {
+		d := revs[0].Bar.Date
+		if !start.IsZero() && d.Before(start) {
+			continue
+		}
+		if !end.IsZero() && d.After(end) {
+			continue
+		}
+		h.bars = append(h.bars, append([]revision(nil), revs...))
+	}
+	sort.Slice(h.bars, func(i, j int) bool { return h.bars[i][0].Bar.Date.Before(h.bars[j][0].Bar.Date) })
+	return h, nil
+}
+
+// AsOf implements models.BarHistory. A zero knownAt returns the latest revisions.
+// For bars we only started recording after knownAt, the earliest revision we have
+// is the best available answer and is used as is.
+func (h *History) AsOf(knownAt time.Time) *models.PriceFrame {
+	pf := &models.PriceFrame{Ticker: h.ticker, Frequency: h.freq}
+	for _, revs := range h.bars {
+		if !knownAt.IsZero() && revs[0].Bar.Date.After(knownAt) {
+			break
+		}
+		bar := revs[len(revs)-1].Bar
+		if !knownAt.IsZero() {
+			bar = revs[0].Bar
+			for _, rev := range revs[1:] {
+				if rev.RecordedAt.After(knownAt) {
+					break
+				}
+				bar = rev.Bar
+			}
+		}
+		pf.Prices = append(pf.Prices, bar)
+	}
+	return pf
+}
+
+// Recorder wraps an upstream provider and records every bar revision it returns,
+// which lets as-of queries ignore corrections made after the as-of date.
+type Recorder struct {
+	Upstream models.Provider
+	Store    *Store
+}
+
+func (r *Recorder) GetPrices(ctx context.Context, ticker string, start, end time.Time, freq string) (*models.PriceFrame, error) {
+	pf, err := r.Upstream.GetPrices(ctx, ticker, start, end, freq)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Store.Record(pf, time.Now()); err != nil {
+		return nil, fmt.Errorf("barstore: record %s: %w", ticker, err)
+	}
+	return pf, nil
+}
+
+func (r *Recorder) GetHistory(ctx context.Context, ticker string, start, end time.Time, freq string) (models.BarHistory, error) {
+	// Refresh first so the store covers the whole requested range
+	pf, err := r.GetPrices(ctx, ticker, start, end, freq)
+	if err != nil {
+		return nil, err
+	}
+	return r.Store.History(pf.Ticker, pf.Frequency, start, end)
+}
@@ -0,0 +1,100 @@
+package barstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/barstore"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+func day(i int) time.Time {
+	return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+}
+
+func frame(closes ...float64) *models.PriceFrame {
+	pf := &models.PriceFrame{Ticker: "AAPL", Frequency: "1d"}
+	for i, c := range closes {
+		pf.Prices = append(pf.Prices, models.Price{Date: day(i), Open: c, High: c, Low: c, Close: c, Volume: 1000})
+	}
+	return pf
+}
+
+func closes(pf *models.PriceFrame) []float64 {
+	var out []float64
+	for _, p := range pf.Prices {
+		out = append(out, p.Close)
+	}
+	return out
+}
+
+// A bar revised later shows its first version to every as-of time before the
+// revision was recorded, and the revision from then on.
+func TestAsOfRevision(t *testing.T) {
+	dir := t.TempDir()
+	s, err := barstore.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := day(2).Add(time.Hour)
+	revised := day(3)
+	if n, err := s.Record(frame(10, 11, 12), recorded); n != 3 || err != nil {
+		t.Fatalf("Record = %d, %v, want 3 bars", n, err)
+	}
+	// Bar 1 corrected, bar 3 new, bars 0 and 2 unchanged
+	if n, err := s.Record(frame(10, 11.5, 12, 13), revised); n != 2 || err != nil {
+		t.Fatalf("Record of the revision = %d, %v, want 2", n, err)
+	}
+	if n, _ := s.Record(frame(10, 11.5, 12, 13), revised.Add(time.Hour)); n != 0 {
+		t.Errorf("recording the same bars again wrote %d", n)
+	}
+
+	tests := []struct {
+		name    string
+		knownAt time.Time
+		want    []float64
+	}{
+		{"first recording", recorded, []float64{10, 11, 12}},
+		{"just before the revision", revised.Add(-time.Nanosecond), []float64{10, 11, 12}},
+		{"at the revision", revised, []float64{10, 11.5, 12, 13}},
+		{"latest", time.Time{}, []float64{10, 11.5, 12, 13}},
+		// Recorded only later, the first version is the best answer we have
+		{"before recording", day(1), []float64{10, 11}},
+	}
+	// Both from memory and from the file, as after a restart
+	reopened, err := barstore.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, store := range []*barstore.Store{s, reopened} {
+		h, err := store.History("aapl", "1d", time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range tests {
+			if got := closes(h.AsOf(tt.knownAt)); !equal(got, tt.want) {
+				t.Errorf("%s: closes %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	}
+
+	h, err := s.History("AAPL", "1d", day(1), day(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := closes(h.AsOf(recorded)); !equal(got, []float64{11, 12}) {
+		t.Errorf("bars 1 to 2 as of the first recording: %v", got)
+	}
+}
+
+func equal(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,205 @@
+// Package cache is the shared cache of computed scores and raw bars. Values
+// are bytes with a TTL, kept in process memory or in a Redis-compatible
+// server so several replicas share one copy and one provider fetch.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores bytes by key. Keys look like "<kind>:<TICKER>:<rest>", so the
+// entries of one ticker can be found with TickerPattern.
+type Cache interface {
+	// Get reports false on a miss.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, val []byte, ttl time.Duration) error
+	// Delete removes keys and returns how many existed.
+	Delete(ctx context.Context, keys ...string) (int, error)
+	// Keys lists keys matching a glob pattern (*, ?, [...], \ escapes).
+	Keys(ctx context.Context, pattern string) ([]string, error)
+	// TTL is the time key has left, negative if it does not expire.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// Len is the number of entries.
+	Len(ctx context.Context) (int, error)
+	Close() error
+}
+
+// Locker is implemented by shared backends, where another replica may be
+// computing the same key right now.
+type Locker interface {
+	// Lock takes the lock of key for at most ttl, false if somebody holds it.
+	Lock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	Unlock(ctx context.Context, key string) error
+}
+
+// Key builds a cache key of kind for ticker.
+func Key(kind, ticker string, rest ...string) string {
+	return kind + ":" + strings.ToUpper(ticker) + ":" + strings.Join(rest, ":")
+}
+
+// TickerPattern matches every key of ticker, of any kind.
+func TickerPattern(ticker string) string {
+	return "*:" + escapeGlob(strings.ToUpper(ticker)) + ":*"
+}
+
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if strings.ContainsRune(`*?[]\`, c) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// lockWait is how long a replica waits for another one computing the same key
+// before it gives up and computes itself.
+const lockWait = 15 * time.Second
+
+// fillTimeout bounds one shared computation: waiting for another replica,
+// then computing itself.
+const fillTimeout = 2 * lockWait
+
+// Fetch returns the value under key, or computes, stores and returns it.
+// Concurrent misses of a key in this process share one computation, and with
+// a Locker backend other replicas wait for it too instead of all hitting the
+// provider at once. An unreachable cache is a miss, never an error.
+func Fetch[T any](ctx context.Context, c Cache, key string, ttl time.Duration, compute func(context.Context) (T, error)) (v T, hit bool, err error) {
+	if v, ok := load[T](ctx, c, key); ok {
+		return v, true, nil
+	}
+	res, err := flights.do(ctx, key, func() (interface{}, error) {
+		// Shared by every caller of key: the first one going away must not
+		// cancel it for the others, so it only keeps ctx's values
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fillTimeout)
+		defer cancel()
+		return fill(ctx, c, key, ttl, compute)
+	})
+	if err != nil {
+		return v, false, err
+	}
+	return res.(T), false, nil
+}
+
+func fill[T any](ctx context.Context, c Cache, key string, ttl time.Duration, compute func(context.Context) (T, error)) (T, error) {
+	if l, ok := c.(Locker); ok {
+		got, err := l.Lock(ctx, key, lockWait)
+		switch {
+		case err != nil:
+			slog.WarnContext(ctx, "cache lock failed", "key", key, "err", err)
+		case got:
+			defer func() { _ = l.Unlock(context.WithoutCancel(ctx), key) }()
+		default:
+			// Someone else is on it, wait for their result
+			if v, ok := await[T](ctx, c, key); ok {
+				return v, nil
+			}
+		}
+	}
+
+	v, err := compute(ctx)
+	if err != nil {
+		return v, err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		slog.WarnContext(ctx, "cache encode failed", "key", key, "err", err)
+		return v, nil
+	}
+	if err := c.Set(ctx, key, buf.Bytes(), ttl); err != nil {
+		slog.WarnContext(ctx, "cache set failed", "key", key, "err", err)
+	}
+	return v, nil
+}
+
+// await polls for key until it appears or the lock would have expired.
+func await[T any](ctx context.Context, c Cache, key string) (T, bool) {
+	var zero T
+	deadline := time.NewTimer(lockWait)
+	defer deadline.Stop()
+	tick := time.NewTicker(50 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return zero, false
+		case <-deadline.C:
+			return zero, false
+		case <-tick.C:
+			if v, ok := load[T](ctx, c, key); ok {
+				return v, true
+			}
+		}
+	}
+}
+
+func load[T any](ctx context.Context, c Cache, key string) (T, bool) {
+	var v T
+	b, ok, err := c.Get(ctx, key)
+	if err != nil {
+		slog.WarnContext(ctx, "cache get failed", "key", key, "err", err)
+		return v, false
+	}
+	if !ok {
+		return v, false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v); err != nil {
+		// Written by an older build, compute it again
+		slog.WarnContext(ctx, "cache decode failed", "key", key, "err", err)
+		return v, false
+	}
+	return v, true
+}
+
+// group runs one call per key at a time, the other callers wait for its result.
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+var flights = &group{calls: make(map[string]*call)}
+
+// do runs fn once for key in its own goroutine. A caller whose ctx ends
+// stops waiting, fn carries on for the others and fills the cache.
+func (g *group) do(ctx context.Context, key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	c, ok := g.calls[key]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		g.calls[key] = c
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					c.err = fmt.Errorf("cache: computing %s panicked: %v", key, r)
+				}
+				g.mu.Lock()
+				delete(g.calls, key)
+				g.mu.Unlock()
+				close(c.done)
+			}()
+			c.val, c.err = fn()
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
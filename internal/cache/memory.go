@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"path"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+// Memory is the process-local backend, fine for a single replica.
+type Memory struct {
+	c *gocache.Cache
+}
+
+// NewMemory purges expired entries every cleanup.
+func NewMemory(cleanup time.Duration) *Memory {
+	return &Memory{c: gocache.New(gocache.NoExpiration, cleanup)}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, ok := m.c.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return v.([]byte), true, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	m.c.Set(key, val, ttl)
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, keys ...string) (int, error) {
+	n := 0
+	for _, k := range keys {
+		if _, ok := m.c.Get(k); ok {
+			n++
+		}
+		m.c.Delete(k)
+	}
+	return n, nil
+}
+
+func (m *Memory) Keys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	for k := range m.c.Items() {
+		ok, err := path.Match(pattern, k)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (m *Memory) TTL(ctx context.Context, key string) (time.Duration, error) {
+	_, exp, ok := m.c.GetWithExpiration(key)
+	if !ok {
+		return 0, nil
+	}
+	if exp.IsZero() {
+		return -1, nil
+	}
+	return time.Until(exp), nil
+}
+
+func (m *Memory) Len(ctx context.Context) (int, error) {
+	// ItemCount would include expired entries not purged yet
+	return len(m.c.Items()), nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Provider serves raw bars from the cache, so replicas asking for the same
+// ticker share one upstream fetch. Open-ended ranges include the live bar,
+// keep TTL short. Start is rounded down to the UTC day, callers asking for
+// "the last three months" from a moving now get a few bars more and share a key.
+type Provider struct {
+	Upstream models.Provider
+	Cache    Cache
+	TTL      time.Duration
+}
+
+func (p *Provider) GetPrices(ctx context.Context, ticker string, start, end time.Time, freq string) (*models.PriceFrame, error) {
+	if !start.IsZero() {
+		start = start.UTC().Truncate(24 * time.Hour)
+	}
+	key := Key("bars", ticker, freq, rangeKey(start), rangeKey(end))
+	pf, _, err := Fetch(ctx, p.Cache, key, p.TTL, func(ctx context.Context) (*models.PriceFrame, error) {
+		return p.Upstream.GetPrices(ctx, ticker, start, end, freq)
+	})
+	return pf, err
+}
+
+func rangeKey(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("20060102T150405")
+}
@@ -0,0 +1,370 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redis talks RESP2 to a Redis-compatible server (Redis, Valkey, KeyDB,
+// Dragonfly). Only the handful of commands the cache needs are implemented.
+type Redis struct {
+	addr     string
+	username string
+	password string
+	db       int
+	prefix   string // namespace, so a shared server can hold other data too
+	timeout  time.Duration
+
+	pool chan *redisConn
+
+	mu    sync.Mutex
+	locks map[string]string // token of every lock held, by key
+}
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// RedisError is an error reply of the server.
+type RedisError string
+
+func (e RedisError) Error() string { return "redis: " + string(e) }
+
+var errNil = errors.New("redis: nil")
+
+// NewRedis parses redis://[user:password@]host[:port][/db] and checks the
+// server answers. Every key gets prefix.
+func NewRedis(rawURL, prefix string) (*Redis, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("redis url: %w", err)
+	}
+	if u.Scheme != "redis" {
+		return nil, fmt.Errorf("redis url: scheme %q, expected redis", u.Scheme)
+	}
+	r := &Redis{
+		addr:    u.Host,
+		prefix:  prefix,
+		timeout: 2 * time.Second,
+		pool:    make(chan *redisConn, 16),
+		locks:   make(map[string]string),
+	}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		r.password, _ = u.User.Password()
+		if r.password == "" {
+			// redis://secret@host, the old form without a user
+			r.password = u.User.Username()
+		} else {
+			r.username = u.User.Username()
+		}
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if r.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("redis url: database %q", db)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	if _, err := r.do(ctx, "PING"); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, err := r.do(ctx, "GET", r.prefix+key)
+	if err == errNil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	b, _ := v.([]byte)
+	return b, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	_, err := r.do(ctx, "SET", r.prefix+key, val, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) (int, error) {
+	total := 0
+	for len(keys) > 0 {
+		n := min(len(keys), 500)
+		args := []interface{}{"DEL"}
+		for _, k := range keys[:n] {
+			args = append(args, r.prefix+k)
+		}
+		v, err := r.do(ctx, args...)
+		if err != nil {
+			return total, err
+		}
+		d, _ := v.(int64)
+		total += int(d)
+		keys = keys[n:]
+	}
+	return total, nil
+}
+
+func (r *Redis) Keys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	cursor := "0"
+	for {
+		v, err := r.do(ctx, "SCAN", cursor, "MATCH", escapeGlob(r.prefix)+pattern, "COUNT", "500")
+		if err != nil {
+			return nil, err
+		}
+		reply, ok := v.([]interface{})
+		if !ok || len(reply) != 2 {
+			return nil, fmt.Errorf("redis: unexpected SCAN reply")
+		}
+		next, _ := reply[0].([]byte)
+		batch, _ := reply[1].([]interface{})
+		for _, k := range batch {
+			if b, ok := k.([]byte); ok {
+				keys = append(keys, strings.TrimPrefix(string(b), r.prefix))
+			}
+		}
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return keys, nil
+		}
+	}
+}
+
+func (r *Redis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	v, err := r.do(ctx, "PTTL", r.prefix+key)
+	if err != nil {
+		return 0, err
+	}
+	ms, _ := v.(int64)
+	switch ms {
+	case -2: // no such key
+		return 0, nil
+	case -1:
+		return -1, nil
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// Len counts the keys under the prefix. SCAN walks the whole keyspace, fine
+// for a dedicated cache server of modest size.
+func (r *Redis) Len(ctx context.Context) (int, error) {
+	keys, err := r.Keys(ctx, "*")
+	return len(keys), err
+}
+
+// UnlockScript deletes the lock KEYS[1] only if it still holds the token
+// ARGV[1]. A lock that expired and was taken by another replica is left alone.
+const UnlockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+func (r *Redis) Lock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return false, err
+	}
+	token := hex.EncodeToString(b[:])
+	_, err := r.do(ctx, "SET", r.prefix+"lock:"+key, token, "NX", "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err == errNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	r.locks[key] = token
+	r.mu.Unlock()
+	return true, nil
+}
+
+func (r *Redis) Unlock(ctx context.Context, key string) error {
+	r.mu.Lock()
+	token, ok := r.locks[key]
+	delete(r.locks, key)
+	r.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	_, err := r.do(ctx, "EVAL", UnlockScript, "1", r.prefix+"lock:"+key, token)
+	return err
+}
+
+func (r *Redis) Close() error {
+	for {
+		select {
+		case c := <-r.pool:
+			c.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do sends one command and reads its reply: []byte for bulk and simple
+// strings, int64, []interface{} for arrays, errNil for a nil reply.
+func (r *Redis) do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	c, err := r.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(r.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = c.SetDeadline(deadline)
+
+	v, err := c.roundTrip(args)
+	var re RedisError
+	if err != nil && err != errNil && !errors.As(err, &re) {
+		// The connection is in an unknown state, don't reuse it
+		c.Close()
+		return nil, err
+	}
+	select {
+	case r.pool <- c:
+	default:
+		c.Close()
+	}
+	return v, err
+}
+
+func (r *Redis) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-r.pool:
+		return c, nil
+	default:
+	}
+	var d net.Dialer
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	nc, err := d.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{Conn: nc, r: bufio.NewReader(nc)}
+	_ = c.SetDeadline(time.Now().Add(r.timeout))
+	if r.password != "" {
+		args := []interface{}{"AUTH", r.password}
+		if r.username != "" {
+			args = []interface{}{"AUTH", r.username, r.password}
+		}
+		if _, err := c.roundTrip(args); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if r.db != 0 {
+		if _, err := c.roundTrip([]interface{}{"SELECT", strconv.Itoa(r.db)}); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *redisConn) roundTrip(args []interface{}) (interface{}, error) {
+	if _, err := c.Write(appendCommand(nil, args)); err != nil {
+		return nil, err
+	}
+	return ReadReply(c.r)
+}
+
+// appendCommand encodes args as a RESP array of bulk strings.
+func appendCommand(b []byte, args []interface{}) []byte {
+	b = append(b, '*')
+	b = strconv.AppendInt(b, int64(len(args)), 10)
+	b = append(b, '\r', '\n')
+	for _, a := range args {
+		var s []byte
+		switch v := a.(type) {
+		case string:
+			s = []byte(v)
+		case []byte:
+			s = v
+		default:
+			s = []byte(fmt.Sprint(v))
+		}
+		b = append(b, '$')
+		b = strconv.AppendInt(b, int64(len(s)), 10)
+		b = append(b, '\r', '\n')
+		b = append(b, s...)
+		b = append(b, '\r', '\n')
+	}
+	return b
+}
+
+// ReadReply reads one RESP2 value. Error replies come back as RedisError, nil
+// bulk strings and arrays as an error too.
+func ReadReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, RedisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, errNil
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad array length %q", line)
+		}
+		if n < 0 {
+			return nil, errNil
+		}
+		out := make([]interface{}, n)
+		for i := range out {
+			v, err := ReadReply(r)
+			if err != nil && err != errNil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply type %q", line[0])
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
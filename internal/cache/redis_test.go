@@ -0,0 +1,271 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+	"github.com/iwanlebron/stock-analysis/internal/cache/resptest"
+)
+
+// replica is a Redis backend on srv, as one server replica would open it.
+func replica(t *testing.T, srv *resptest.Server) *cache.Redis {
+	t.Helper()
+	r, err := cache.NewRedis(srv.URL, "fg:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func newServer(t *testing.T) *resptest.Server {
+	srv := resptest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRedisGetSetKeys(t *testing.T) {
+	ctx := context.Background()
+	r := replica(t, newServer(t))
+
+	if _, ok, err := r.Get(ctx, "score:AAPL:x"); ok || err != nil {
+		t.Fatalf("Get on an empty server = %v, %v, want a miss", ok, err)
+	}
+	for _, k := range []string{cache.Key("score", "aapl", "1d"), cache.Key("bars", "AAPL", "1h"), cache.Key("score", "MSFT", "1d"), cache.Key("score", "A*B", "1d")} {
+		if err := r.Set(ctx, k, []byte("v:"+k), time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, ok, err := r.Get(ctx, "score:AAPL:1d")
+	if err != nil || !ok || string(b) != "v:score:AAPL:1d" {
+		t.Fatalf("Get = %q, %v, %v", b, ok, err)
+	}
+	if ttl, err := r.TTL(ctx, "score:AAPL:1d"); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL = %v, %v, want up to a minute", ttl, err)
+	}
+	if ttl, err := r.TTL(ctx, "score:NONE:1d"); err != nil || ttl != 0 {
+		t.Errorf("TTL of a missing key = %v, %v, want 0", ttl, err)
+	}
+
+	keys, err := r.Keys(ctx, cache.TickerPattern("AAPL"))
+	slices.Sort(keys)
+	if want := []string{"bars:AAPL:1h", "score:AAPL:1d"}; err != nil || !slices.Equal(keys, want) {
+		t.Errorf("Keys(AAPL) = %v, %v, want %v", keys, err, want)
+	}
+	// Glob characters in a ticker are literal
+	if keys, err := r.Keys(ctx, cache.TickerPattern("A*B")); err != nil || len(keys) != 1 {
+		t.Errorf("Keys(A*B) = %v, %v, want only its own key", keys, err)
+	}
+	if n, err := r.Len(ctx); err != nil || n != 4 {
+		t.Errorf("Len = %d, %v, want 4", n, err)
+	}
+
+	if n, err := r.Delete(ctx, "score:AAPL:1d", "score:NONE:1d"); err != nil || n != 1 {
+		t.Errorf("Delete = %d, %v, want 1", n, err)
+	}
+	if _, ok, _ := r.Get(ctx, "score:AAPL:1d"); ok {
+		t.Error("deleted key still there")
+	}
+}
+
+func TestRedisExpiry(t *testing.T) {
+	ctx := context.Background()
+	r := replica(t, newServer(t))
+	if err := r.Set(ctx, "k", []byte("v"), 30*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if _, ok, _ := r.Get(ctx, "k"); ok {
+		t.Error("key outlived its TTL")
+	}
+}
+
+func TestRedisAuth(t *testing.T) {
+	srv := newServer(t)
+	srv.RequirePass("s3cret")
+
+	if _, err := cache.NewRedis(srv.URL, ""); err == nil || !strings.Contains(err.Error(), "NOAUTH") {
+		t.Errorf("no password: err = %v, want NOAUTH", err)
+	}
+	url := strings.Replace(srv.URL, "redis://", "redis://s3cret@", 1)
+	r, err := cache.NewRedis(url, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if err := r.Set(context.Background(), "k", []byte("v"), time.Minute); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRedisLock(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	a, b := replica(t, srv), replica(t, srv)
+
+	if got, err := a.Lock(ctx, "k", time.Minute); !got || err != nil {
+		t.Fatalf("a.Lock = %v, %v, want the lock", got, err)
+	}
+	if got, err := b.Lock(ctx, "k", time.Minute); got || err != nil {
+		t.Fatalf("b.Lock = %v, %v while a holds it", got, err)
+	}
+	// b never held it, its unlock must not free a's lock
+	if err := b.Unlock(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := b.Lock(ctx, "k", time.Minute); got {
+		t.Fatal("b took the lock after unlocking one it didn't hold")
+	}
+	if err := a.Unlock(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := b.Lock(ctx, "k", time.Minute); !got || err != nil {
+		t.Fatalf("b.Lock after a.Unlock = %v, %v, want the lock", got, err)
+	}
+}
+
+// A lock that expired and went to another replica stays with that replica
+// when the first one finally unlocks.
+func TestRedisUnlockAfterExpiry(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	a, b, c := replica(t, srv), replica(t, srv), replica(t, srv)
+
+	if got, _ := a.Lock(ctx, "k", 30*time.Millisecond); !got {
+		t.Fatal("a didn't get the lock")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if got, _ := b.Lock(ctx, "k", time.Minute); !got {
+		t.Fatal("b didn't get the expired lock")
+	}
+	if err := a.Unlock(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := c.Lock(ctx, "k", time.Minute); got {
+		t.Error("a's late unlock released b's lock")
+	}
+}
+
+func TestFetchRedis(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	r := replica(t, srv)
+
+	var computed atomic.Int32
+	compute := func(context.Context) (int, error) {
+		computed.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, _, err := cache.Fetch(ctx, r, "score:AAPL:fetch", time.Minute, compute); v != 42 || err != nil {
+				t.Errorf("Fetch = %d, %v, want 42", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := computed.Load(); n != 1 {
+		t.Errorf("computed %d times, want once", n)
+	}
+
+	v, hit, err := cache.Fetch(ctx, r, "score:AAPL:fetch", time.Minute, compute)
+	if v != 42 || !hit || err != nil {
+		t.Errorf("second Fetch = %d, %v, %v, want a hit", v, hit, err)
+	}
+	if n := srv.Calls("EVAL"); n != 1 {
+		t.Errorf("%d unlocks, want 1", n)
+	}
+	if got, _ := r.Lock(ctx, "score:AAPL:fetch", time.Minute); !got {
+		t.Error("Fetch left its lock behind")
+	}
+}
+
+// A replica that finds the lock taken waits for the holder's value instead of
+// computing it too.
+func TestFetchWaitsForOtherReplica(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	a, b := replica(t, srv), replica(t, srv)
+	const key = "score:AAPL:replica"
+
+	if got, _ := b.Lock(ctx, key, time.Minute); !got {
+		t.Fatal("b didn't get the lock")
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		var buf bytes.Buffer
+		_ = gob.NewEncoder(&buf).Encode(7)
+		_ = b.Set(ctx, key, buf.Bytes(), time.Minute)
+		_ = b.Unlock(ctx, key)
+	}()
+
+	v, hit, err := cache.Fetch(ctx, a, key, time.Minute, func(context.Context) (int, error) {
+		t.Error("computed while another replica held the lock")
+		return 0, nil
+	})
+	if v != 7 || hit || err != nil {
+		t.Errorf("Fetch = %d, %v, %v, want b's 7", v, hit, err)
+	}
+}
+
+// The first caller giving up doesn't cancel the computation the others wait for.
+func TestFetchFirstCallerCancels(t *testing.T) {
+	c := cache.NewMemory(time.Minute)
+	defer c.Close()
+	const key = "score:AAPL:cancel"
+
+	release := make(chan struct{})
+	compute := func(ctx context.Context) (int, error) {
+		select {
+		case <-release:
+			return 1, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, _, err := cache.Fetch(first, c, key, time.Minute, compute)
+		firstErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	second := make(chan int)
+	go func() {
+		v, _, err := cache.Fetch(context.Background(), c, key, time.Minute, compute)
+		if err != nil {
+			t.Error(err)
+		}
+		second <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller err = %v, want context.Canceled", err)
+	}
+	close(release)
+	if v := <-second; v != 1 {
+		t.Errorf("second caller got %d, want 1", v)
+	}
+	if _, ok, _ := c.Get(context.Background(), key); !ok {
+		t.Error("value not cached")
+	}
+}
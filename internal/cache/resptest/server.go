@@ -0,0 +1,307 @@
+// Package resptest is an in-process stand-in for a Redis server, like
+// net/http/httptest is for HTTP: enough of RESP2 for the cache backend,
+// for integration tests and for trying multi-replica setups without Redis.
+package resptest
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+)
+
+// Server serves GET, SET (EX, PX, NX), DEL, SCAN, PTTL, DBSIZE, FLUSHDB,
+// PING, AUTH, SELECT and QUIT from memory, and EVAL of cache.UnlockScript
+// only. Databases are not separated.
+type Server struct {
+	// URL is the redis:// URL of the server, without credentials.
+	URL string
+
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu       sync.Mutex
+	password string
+	data     map[string]entry
+	cmds     map[string]int // commands served, by name
+}
+
+type entry struct {
+	val []byte
+	exp time.Time // zero: no expiry
+}
+
+// NewServer starts a server on a loopback port. Close it when done.
+func NewServer() *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("resptest: listen: %v", err))
+	}
+	s := &Server{
+		URL:  "redis://" + ln.Addr().String(),
+		ln:   ln,
+		data: make(map[string]entry),
+		cmds: make(map[string]int),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// RequirePass makes connections AUTH with password first, like requirepass.
+func (s *Server) RequirePass(password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.password = password
+}
+
+// Calls is how many times the command name was received.
+func (s *Server) Calls(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cmds[strings.ToUpper(name)]
+}
+
+// Close stops accepting connections and waits for the open ones to end.
+func (s *Server) Close() {
+	s.ln.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	var conns sync.WaitGroup
+	defer conns.Wait()
+	var mu sync.Mutex
+	open := map[net.Conn]bool{}
+	defer func() {
+		mu.Lock()
+		for c := range open {
+			c.Close()
+		}
+		mu.Unlock()
+	}()
+
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		open[c] = true
+		mu.Unlock()
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			s.handle(c)
+			mu.Lock()
+			delete(open, c)
+			mu.Unlock()
+		}()
+	}
+}
+
+func (s *Server) handle(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	w := bufio.NewWriter(c)
+	s.mu.Lock()
+	password := s.password
+	s.mu.Unlock()
+	authed := password == ""
+	for {
+		v, err := cache.ReadReply(r)
+		if err != nil {
+			return
+		}
+		parts, ok := v.([]interface{})
+		if !ok || len(parts) == 0 {
+			writeError(w, "ERR protocol error")
+			w.Flush()
+			return
+		}
+		args := make([]string, len(parts))
+		for i, p := range parts {
+			b, _ := p.([]byte)
+			args[i] = string(b)
+		}
+		name := strings.ToUpper(args[0])
+
+		s.mu.Lock()
+		s.cmds[name]++
+		s.mu.Unlock()
+
+		switch {
+		case name == "QUIT":
+			writeSimple(w, "OK")
+			w.Flush()
+			return
+		case name == "AUTH":
+			if args[len(args)-1] != password {
+				writeError(w, "WRONGPASS invalid username-password pair")
+			} else {
+				authed = true
+				writeSimple(w, "OK")
+			}
+		case !authed:
+			writeError(w, "NOAUTH Authentication required.")
+		default:
+			s.exec(w, name, args[1:])
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) exec(w *bufio.Writer, name string, args []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	get := func(k string) (entry, bool) {
+		e, ok := s.data[k]
+		if ok && !e.exp.IsZero() && !now.Before(e.exp) {
+			delete(s.data, k)
+			return entry{}, false
+		}
+		return e, ok
+	}
+
+	switch name {
+	case "PING":
+		writeSimple(w, "PONG")
+	case "SELECT":
+		writeSimple(w, "OK")
+	case "GET":
+		if len(args) != 1 {
+			writeError(w, "ERR wrong number of arguments for 'get' command")
+			return
+		}
+		if e, ok := get(args[0]); ok {
+			writeBulk(w, e.val)
+		} else {
+			w.WriteString("$-1\r\n")
+		}
+	case "SET":
+		if len(args) < 2 {
+			writeError(w, "ERR wrong number of arguments for 'set' command")
+			return
+		}
+		e := entry{val: []byte(args[1])}
+		nx := false
+		for i := 2; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "NX":
+				nx = true
+			case "EX", "PX":
+				if i+1 >= len(args) {
+					writeError(w, "ERR syntax error")
+					return
+				}
+				n, err := strconv.ParseInt(args[i+1], 10, 64)
+				if err != nil || n <= 0 {
+					writeError(w, "ERR invalid expire time in 'set' command")
+					return
+				}
+				unit := time.Millisecond
+				if strings.ToUpper(args[i]) == "EX" {
+					unit = time.Second
+				}
+				e.exp = now.Add(time.Duration(n) * unit)
+				i++
+			default:
+				writeError(w, "ERR syntax error")
+				return
+			}
+		}
+		if _, exists := get(args[0]); nx && exists {
+			w.WriteString("$-1\r\n")
+			return
+		}
+		s.data[args[0]] = e
+		writeSimple(w, "OK")
+	case "DEL":
+		n := 0
+		for _, k := range args {
+			if _, ok := get(k); ok {
+				delete(s.data, k)
+				n++
+			}
+		}
+		writeInt(w, int64(n))
+	case "PTTL":
+		if len(args) != 1 {
+			writeError(w, "ERR wrong number of arguments for 'pttl' command")
+			return
+		}
+		e, ok := get(args[0])
+		switch {
+		case !ok:
+			writeInt(w, -2)
+		case e.exp.IsZero():
+			writeInt(w, -1)
+		default:
+			writeInt(w, e.exp.Sub(now).Milliseconds())
+		}
+	case "SCAN":
+		// One pass returns everything, cursor 0 means done
+		pattern := "*"
+		for i := 1; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				pattern = args[i+1]
+			}
+		}
+		var keys []string
+		for k := range s.data {
+			if _, ok := get(k); !ok {
+				continue
+			}
+			if ok, _ := path.Match(pattern, k); ok {
+				keys = append(keys, k)
+			}
+		}
+		w.WriteString("*2\r\n")
+		writeBulk(w, []byte("0"))
+		fmt.Fprintf(w, "*%d\r\n", len(keys))
+		for _, k := range keys {
+			writeBulk(w, []byte(k))
+		}
+	case "EVAL":
+		// No Lua here, just the one script the cache sends
+		if len(args) != 4 || args[0] != cache.UnlockScript || args[1] != "1" {
+			writeError(w, "ERR resptest: only cache.UnlockScript can be evaluated")
+			return
+		}
+		if e, ok := get(args[2]); ok && string(e.val) == args[3] {
+			delete(s.data, args[2])
+			writeInt(w, 1)
+		} else {
+			writeInt(w, 0)
+		}
+	case "DBSIZE":
+		writeInt(w, int64(len(s.data)))
+	case "FLUSHDB", "FLUSHALL":
+		s.data = make(map[string]entry)
+		writeSimple(w, "OK")
+	default:
+		writeError(w, fmt.Sprintf("ERR unknown command '%s'", strings.ToLower(name)))
+	}
+}
+
+func writeSimple(w *bufio.Writer, s string) { w.WriteString("+" + s + "\r\n") }
+
+func writeError(w *bufio.Writer, s string) { w.WriteString("-" + s + "\r\n") }
+
+func writeInt(w *bufio.Writer, n int64) { fmt.Fprintf(w, ":%d\r\n", n) }
+
+func writeBulk(w *bufio.Writer, b []byte) {
+	fmt.Fprintf(w, "$%d\r\n", len(b))
+	w.Write(b)
+	w.WriteString("\r\n")
+}
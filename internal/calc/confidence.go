@@ -0,0 +1,71 @@
+package calc
+
+import (
+	"math"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Confidence grades of a score
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// ConfidenceConfig holds the cut-offs of the confidence grade.
+type ConfidenceConfig struct {
+	LowSamples   float64 // share of the requested norm window below which a score is low
+	LowCoverage  float64 // share of the weight below which a score is low
+	HighCoverage float64 // share of the weight a high score needs, besides the full window
+}
+
+var DefaultConfidence = ConfidenceConfig{LowSamples: 0.25, LowCoverage: 0.5, HighCoverage: 0.8}
+
+// quality fills Quality of res, bar i scored with norm window w, from the
+// raw components. A bar without a score has none.
+func quality(res *models.ScoreResult, raw *[numComponents][]float64, i, w int, cfg Config) {
+	res.Quality = models.DataQuality{}
+	if math.IsNaN(res.Score) {
+		return
+	}
+	weights := cfg.Weights
+	if weights == nil {
+		weights = Weights
+	}
+	q := models.DataQuality{Window: w, Samples: w}
+	total, present := 0.0, 0.0
+	for c, id := range Components {
+		total += weights[id]
+		if math.IsNaN(IndicatorValue(*res, id)) || weights[id] <= 0 {
+			continue
+		}
+		q.Components++
+		present += weights[id]
+		// Ranked against the valid values of the window only
+		valid := 0
+		for _, v := range raw[c][max(i-w+1, 0) : i+1] {
+			if !math.IsNaN(v) {
+				valid++
+			}
+		}
+		q.Samples = min(q.Samples, valid)
+	}
+	if total > 0 {
+		q.Coverage = present / total
+	}
+	q.Confidence = confidenceOf(q, cfg.NormWindow, DefaultConfidence)
+	res.Quality = q
+}
+
+// confidenceOf grades q against the requested norm window: high with the
+// full window and most of the weight, low with a fraction of either.
+func confidenceOf(q models.DataQuality, window int, cc ConfidenceConfig) string {
+	switch {
+	case float64(q.Samples) < cc.LowSamples*float64(window) || q.Coverage < cc.LowCoverage:
+		return ConfidenceLow
+	case q.Samples >= window && q.Coverage >= cc.HighCoverage:
+		return ConfidenceHigh
+	}
+	return ConfidenceMedium
+}
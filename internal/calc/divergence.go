@@ -0,0 +1,152 @@
+package calc
+
+import (
+	"math"
+	"slices"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Divergence kinds
+const (
+	Bearish = "bearish" // price made a higher high, the indicator a lower high
+	Bullish = "bullish" // price made a lower low, the indicator a higher low
+)
+
+// DivergenceConfig tunes FindDivergences.
+type DivergenceConfig struct {
+	Pivot  int // bars on each side a swing high or low must beat
+	MaxGap int // most bars between the two pivots compared
+}
+
+var DefaultDivergence = DivergenceConfig{Pivot: 5, MaxGap: 60}
+
+// Divergence is a disagreement between two price pivots and the indicator at
+// the same bars. Start and End index the series.
+type Divergence struct {
+	Kind        string
+	Start, End  int
+	Confirmed   int     // first bar the End pivot is known on, Pivot bars after it
+	PriceChange float64 // End price over Start price, minus 1, 0 if Start price isn't positive
+	ValueChange float64 // End value minus Start value
+	Strength    float64 // how far the indicator moved against price, in points
+}
+
+// FindDivergences compares consecutive swing highs (bearish) and swing lows
+// (bullish) of price with values at the same bars, oldest first. A pivot
+// needs cfg.Pivot bars after it, so the newest bars can't end a divergence
+// yet. Pivots where the value is NaN are skipped.
+func FindDivergences(price, value []float64, cfg DivergenceConfig) []Divergence {
+	var out []Divergence
+	for _, high := range []bool{true, false} {
+		prev := -1
+		for _, b := range pivots(price, cfg.Pivot, high) {
+			if math.IsNaN(value[b]) {
+				continue
+			}
+			a := prev
+			prev = b
+			if a < 0 || b-a > cfg.MaxGap {
+				continue
+			}
+			dp, dv := price[b]-price[a], value[b]-value[a]
+			kind := ""
+			switch {
+			case high && dp > 0 && dv < 0:
+				kind = Bearish
+			case !high && dp < 0 && dv > 0:
+				kind = Bullish
+			default:
+				continue
+			}
+			d := Divergence{
+				Kind:        kind,
+				Start:       a,
+				End:         b,
+				Confirmed:   b + max(cfg.Pivot, 1),
+				ValueChange: dv,
+				Strength:    math.Abs(dv),
+			}
+			// Futures can settle at or below zero, where a ratio means nothing
+			if price[a] > 0 {
+				d.PriceChange = price[b]/price[a] - 1
+			}
+			out = append(out, d)
+		}
+	}
+	slices.SortStableFunc(out, func(x, y Divergence) int { return x.End - y.End })
+	return out
+}
+
+// pivots are the swing highs (or lows) of x: bars beating the k bars before
+// them and not beaten by the k after. A plateau counts once, at its first bar.
+func pivots(x []float64, k int, high bool) []int {
+	if k < 1 {
+		k = 1
+	}
+	var out []int
+	for i := k; i+k < len(x); i++ {
+		ok := true
+		for j := i - k; j <= i+k && ok; j++ {
+			d := x[i] - x[j]
+			if !high {
+				d = -d
+			}
+			switch {
+			case j < i:
+				ok = d > 0
+			case j > i:
+				ok = d >= 0
+			}
+		}
+		if ok {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// Divergences runs FindDivergences on the closes of results against the
+// score ("score") or a sub-score by component id.
+func Divergences(results []models.ScoreResult, indicator string, cfg DivergenceConfig) []Divergence {
+	price := make([]float64, len(results))
+	value := make([]float64, len(results))
+	for i, r := range results {
+		price[i] = r.Price
+		value[i] = IndicatorValue(r, indicator)
+	}
+	return FindDivergences(price, value, cfg)
+}
+
+// IndicatorValue is the score of r ("score") or one of its sub-scores, NaN
+// for an unknown id.
+func IndicatorValue(r models.ScoreResult, id string) float64 {
+	v := r.Values
+	switch id {
+	case "score":
+		return r.Score
+	case "trend":
+		return v.Trend
+	case "momentum":
+		return v.Momentum
+	case "rsi":
+		return v.RSI
+	case "macd":
+		return v.MACD
+	case "drawdown":
+		return v.Drawdown
+	case "volatility":
+		return v.Vol
+	case "mfi":
+		return v.MFI
+	case "bb_pct_b":
+		return v.BB
+	}
+	return math.NaN()
+}
+
+// IsIndicator reports whether id can be checked for divergence: the score or
+// a component.
+func IsIndicator(id string) bool {
+	return id == "score" || slices.Contains(Components, id)
+}
@@ -0,0 +1,57 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFindDivergences(t *testing.T) {
+	tests := []struct {
+		name   string
+		price  []float64
+		value  []float64
+		kind   string
+		change float64
+	}{
+		{
+			"bearish",
+			[]float64{8, 9, 10, 9, 8, 9, 11, 9, 8},
+			[]float64{50, 55, 70, 60, 50, 55, 60, 55, 50},
+			Bearish, 0.1,
+		},
+		{
+			"bullish",
+			[]float64{12, 11, 10, 11, 12, 11, 8, 11, 12},
+			[]float64{50, 40, 20, 30, 40, 35, 30, 35, 40},
+			Bullish, -0.2,
+		},
+		{
+			// Settled at zero, the change is left at 0 instead of +Inf
+			"zero start price",
+			[]float64{-3, -2, 0, -2, -3, -2, 1, -2, -3},
+			[]float64{50, 55, 70, 60, 50, 55, 60, 55, 50},
+			Bearish, 0,
+		},
+		{
+			"negative start price",
+			[]float64{12, 11, -2, 11, 12, 11, -5, 11, 12},
+			[]float64{50, 40, 20, 30, 40, 35, 30, 35, 40},
+			Bullish, 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindDivergences(tt.price, tt.value, DivergenceConfig{Pivot: 1, MaxGap: 10})
+			if len(got) != 1 {
+				t.Fatalf("got %+v, want one divergence", got)
+			}
+			d := got[0]
+			if d.Kind != tt.kind || d.Start != 2 || d.End != 6 || d.Confirmed != 7 {
+				t.Errorf("got %+v, want %s from 2 to 6 confirmed on 7", d, tt.kind)
+			}
+			if math.Abs(d.PriceChange-tt.change) > 1e-9 {
+				t.Errorf("price change %g, want %g", d.PriceChange, tt.change)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package calc
 
 import (
 	"math"
-	"stock-analysis/internal/models"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
 )
 
 type Config struct {
@@ -13,6 +15,9 @@ type Config struct {
 	VolWindow  int
 	RSIWindow  int
 	DDWindow   int
+	Weights    map[string]float64 // per component, nil uses Weights
+	Labels     string             // label scheme id, "" the default one
+	Smoothing  Smoothing          // signal line, off by default
 }
 
 var DefaultConfig = Config{
@@ -36,6 +41,27 @@ var Weights = map[string]float64{
 	"bb_pct_b":   0.15, // New: Replaces part of trend/volatility
 }
 
+// Components lists the component ids in display order.
+var Components = []string{"trend", "momentum", "rsi", "macd", "drawdown", "volatility", "mfi", "bb_pct_b"}
+
+// WarmupStart moves start back far enough to warm up every indicator.
+func WarmupStart(start time.Time, freq string) time.Time {
+	if start.IsZero() {
+		return start
+	}
+	if freq == "1wk" {
+		// Weekly bars come from daily ones, MA60 alone is more than a year
+		return start.AddDate(-3, 0, 0)
+	}
+	if freq == "1d" {
+		// Approx 2 years buffer for daily
+		// This ensures even if user asks for data starting today, we have enough history to compute indicators
+		return start.AddDate(-2, 0, 0)
+	}
+	// For hourly, maybe 2 months buffer
+	return start.AddDate(0, -2, 0)
+}
+
 func Compute(pf *models.PriceFrame, cfg Config, lang string) []models.ScoreResult {
 	n := len(pf.Prices)
 	if n == 0 {
@@ -55,31 +81,10 @@ func Compute(pf *models.PriceFrame, cfg Config, lang string) []models.ScoreResul
 		lows[i] = p.Low
 	}
 
-	// Adjust norm window if not enough data
-	normWindow := cfg.NormWindow
-	if n < normWindow {
-		normWindow = n
-	}
-	if normWindow < 10 {
-		normWindow = 10 // minimum
-	}
+	normWindow := normWindowFor(n, cfg)
 
 	// 1. Calculate Raw Indicators
-	maFast := SMA(closes, cfg.MAFast)
-	maSlow := SMA(closes, cfg.MASlow)
-	trendRaw := make([]float64, n)
-	for i := 0; i < n; i++ {
-		t1 := 0.0
-		if maFast[i] > 0 {
-			t1 = (closes[i]/maFast[i] - 1.0)
-		}
-		t2 := 0.0
-		if maSlow[i] > 0 {
-			t2 = (closes[i]/maSlow[i] - 1.0)
-		}
-		trendRaw[i] = 0.5*t1 + 0.5*t2
-	}
-
+	trendRaw := Trend(closes, cfg.MAFast, cfg.MASlow)
 	momRaw := Momentum(closes, cfg.MomWindow)
 	rsiRaw := RSI(closes, cfg.RSIWindow)
 	macdRaw := MACD(closes)
@@ -90,23 +95,7 @@ func Compute(pf *models.PriceFrame, cfg Config, lang string) []models.ScoreResul
 	bbRaw := BollingerPercentB(closes, 20, 2.0)     // Standard 20, 2
 
 	// Drawdown (from 252 high)
-	ddRaw := make([]float64, n)
-	// Rolling max
-	for i := 0; i < n; i++ {
-		start := i - cfg.DDWindow + 1
-		if start < 0 {
-			start = 0
-		}
-		maxP := 0.0
-		for j := start; j <= i; j++ {
-			if closes[j] > maxP {
-				maxP = closes[j]
-			}
-		}
-		if maxP > 0 {
-			ddRaw[i] = (closes[i] / maxP) - 1.0
-		}
-	}
+	ddRaw := Drawdown(closes, cfg.DDWindow)
 
 	// 2. Normalize to Scores (0-100)
 	sTrend := RollingScore(trendRaw, normWindow, 1)
@@ -120,6 +109,11 @@ func Compute(pf *models.PriceFrame, cfg Config, lang string) []models.ScoreResul
 
 	// 3. Aggregate
 	results := make([]models.ScoreResult, n)
+	scheme := cfg.scheme()
+	var lab labeler
+	var sm smoothState
+	var reg regimer
+	raws := [numComponents][]float64{trendRaw, momRaw, rsiRaw, macdRaw, ddRaw, volRaw, mfiRaw, bbRaw}
 
 	for i := 0; i < n; i++ {
 		res := models.ScoreResult{
@@ -133,6 +127,8 @@ func Compute(pf *models.PriceFrame, cfg Config, lang string) []models.ScoreResul
 		res.Raw.MACD = macdRaw[i]
 		res.Raw.Drawdown = ddRaw[i]
 		res.Raw.Vol = volRaw[i]
+		res.Raw.MFI = mfiRaw[i]
+		res.Raw.BB = bbRaw[i]
 		// res.Raw.VolSent = volSentRaw[i] // Removed
 
 		// Scores
@@ -145,74 +141,70 @@ func Compute(pf *models.PriceFrame, cfg Config, lang string) []models.ScoreResul
 		res.Values.MFI = sMFI[i]
 		res.Values.BB = sBB[i]
 
-		// Weighted Sum
-		wSum := 0.0
-		scoreSum := 0.0
-
-		add := func(key string, val float64) {
-			if !math.IsNaN(val) {
-				w := Weights[key]
-				scoreSum += val * w
-				wSum += w
-			}
-		}
-
-		add("trend", sTrend[i])
-		add("momentum", sMom[i])
-		add("rsi", sRSI[i])
-		add("macd", sMACD[i])
-		add("drawdown", sDD[i])
-		add("volatility", sVol[i])
-		add("mfi", sMFI[i])
-		add("bb_pct_b", sBB[i])
-
-		if wSum > 0 {
-			res.Score = scoreSum / wSum
-			res.Label = LabelFromScore(res.Score, lang)
-		} else {
-			res.Score = math.NaN()
-			res.Label = "-"
-		}
+		aggregate(&res, cfg.Weights)
+		quality(&res, &raws, i, normWindow, cfg)
+		reg.next(&res, DefaultRegime)
+		lab.label(&res, scheme, lang)
 
 		results[i] = res
+		cfg.Smoothing.smooth(&sm, results, i)
 	}
 
 	return results
 }
 
-func LabelFromScore(s float64, lang string) string {
-	if math.IsNaN(s) {
-		return "-"
+// scheme resolves the label scheme, an unknown id being the default one.
+func (cfg Config) scheme() models.LabelScheme {
+	if s, ok := LookupScheme(cfg.Labels); ok {
+		return s
 	}
-
-	if lang == "en" {
-		if s < 25 {
-			return "Extreme Fear"
-		}
-		if s < 45 {
-			return "Fear"
-		}
-		if s <= 55 {
-			return "Neutral"
-		}
-		if s <= 75 {
-			return "Greed"
-		}
-		return "Extreme Greed"
+	if s, ok := LookupScheme(""); ok {
+		return s
 	}
+	return builtinSchemes[0]
+}
 
-	// Default Chinese
-	if s < 25 {
-		return "极度恐惧"
+// normWindowFor adjusts the norm window if there is not enough data.
+func normWindowFor(n int, cfg Config) int {
+	normWindow := cfg.NormWindow
+	if n < normWindow {
+		normWindow = n
 	}
-	if s < 45 {
-		return "恐惧"
+	if normWindow < 10 {
+		normWindow = 10 // minimum
 	}
-	if s <= 55 {
-		return "中性"
+	return normWindow
+}
+
+// aggregate fills Score from the normalized components, weighting only the
+// ones available. nil weights are the package Weights.
+func aggregate(res *models.ScoreResult, weights map[string]float64) {
+	if weights == nil {
+		weights = Weights
 	}
-	if s <= 75 {
-		return "贪婪"
+	wSum := 0.0
+	scoreSum := 0.0
+
+	add := func(key string, val float64) {
+		if !math.IsNaN(val) {
+			w := weights[key]
+			scoreSum += val * w
+			wSum += w
+		}
+	}
+
+	add("trend", res.Values.Trend)
+	add("momentum", res.Values.Momentum)
+	add("rsi", res.Values.RSI)
+	add("macd", res.Values.MACD)
+	add("drawdown", res.Values.Drawdown)
+	add("volatility", res.Values.Vol)
+	add("mfi", res.Values.MFI)
+	add("bb_pct_b", res.Values.BB)
+
+	if wSum > 0 {
+		res.Score = scoreSum / wSum
+	} else {
+		res.Score = math.NaN()
 	}
-	return "极度贪婪"
 }
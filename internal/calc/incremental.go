@@ -0,0 +1,416 @@
+package calc
+
+import (
+	"math"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Engine is the incremental form of Compute: seed it with history, then push
+// new bars (or update the bar in progress) without recomputing everything.
+// Results always equals Compute over the same bars, bit for bit, so every
+// running sum below is updated in exactly the order the batch code uses.
+type Engine struct {
+	cfg  Config
+	lang string
+
+	prices  []models.Price
+	results []models.ScoreResult
+
+	// Per bar history the rolling windows look back into
+	closes  []float64
+	returns []float64
+	posFlow []float64
+	negFlow []float64
+	raw     [numComponents][]float64
+
+	st engineState
+
+	// Undo information for Update: state before the last bar and the result
+	// of the bar before it, which the last push may have changed
+	prev       engineState
+	prevResult models.ScoreResult
+	canUndo    bool
+}
+
+// Components in aggregate order, with their normalization direction
+const numComponents = 8
+
+var directions = [numComponents]float64{1, 1, 1, 1, 1, -1, 1, 1}
+
+// engineState is everything carried from one bar to the next besides the
+// per bar history.
+type engineState struct {
+	maFast, maSlow, bbMA float64 // SMA running sums
+
+	avgGain, avgLoss float64 // RSI, sums until the window fills
+
+	emaFast, emaSlow, emaSignal float64 // MACD
+
+	prevTP         float64 // MFI typical price of the previous bar
+	sumPos, sumNeg float64
+
+	ddMax []int // indices of the rolling max, decreasing closes
+
+	lab labeler     // label band carried across bars
+	sm  smoothState // signal line filter
+	reg regimer     // regime with its pending switch
+}
+
+func (s engineState) clone() engineState {
+	s.ddMax = append([]int(nil), s.ddMax...)
+	return s
+}
+
+// NewEngine returns an empty engine.
+func NewEngine(cfg Config, lang string) *Engine {
+	return &Engine{cfg: cfg, lang: lang}
+}
+
+// Seed pushes a whole history at once.
+func (e *Engine) Seed(prices []models.Price) {
+	for i, p := range prices {
+		e.push(p, i == len(prices)-1)
+	}
+}
+
+// Push appends a new bar and returns its result.
+func (e *Engine) Push(p models.Price) models.ScoreResult {
+	return e.push(p, true)
+}
+
+// Update replaces the last bar, e.g. a bar still in progress whose close moved,
+// and returns its new result. With no bars yet it is Push.
+func (e *Engine) Update(p models.Price) models.ScoreResult {
+	if !e.canUndo {
+		if len(e.prices) > 0 {
+			// Only the first bar can't be undone: start over
+			*e = *NewEngine(e.cfg, e.lang)
+		}
+		return e.Push(p)
+	}
+
+	n := len(e.prices) - 1
+	e.prices = e.prices[:n]
+	e.results = e.results[:n]
+	e.closes = e.closes[:n]
+	e.returns = e.returns[:n]
+	e.posFlow = e.posFlow[:n]
+	e.negFlow = e.negFlow[:n]
+	for c := range e.raw {
+		e.raw[c] = e.raw[c][:n]
+	}
+	e.st = e.prev
+	if n > 0 {
+		e.results[n-1] = e.prevResult
+	}
+	return e.Push(p)
+}
+
+// Len is the number of bars pushed.
+func (e *Engine) Len() int {
+	return len(e.prices)
+}
+
+// Last returns the result of the newest bar.
+func (e *Engine) Last() (models.ScoreResult, bool) {
+	if len(e.results) == 0 {
+		return models.ScoreResult{}, false
+	}
+	return e.results[len(e.results)-1], true
+}
+
+// Results is the full series, as Compute would return it. The slice is owned
+// by the engine.
+func (e *Engine) Results() []models.ScoreResult {
+	return e.results
+}
+
+func (e *Engine) push(p models.Price, keepUndo bool) models.ScoreResult {
+	e.canUndo = keepUndo && len(e.prices) > 0
+	if e.canUndo {
+		e.prev = e.st.clone()
+		e.prevResult = e.results[len(e.results)-1]
+	}
+
+	i := len(e.prices)
+	e.prices = append(e.prices, p)
+	e.closes = append(e.closes, p.Close)
+	cfg := e.cfg
+
+	// Trend
+	maFast := sma(&e.st.maFast, e.closes, cfg.MAFast)
+	maSlow := sma(&e.st.maSlow, e.closes, cfg.MASlow)
+	t1 := 0.0
+	if maFast > 0 {
+		t1 = (p.Close/maFast - 1.0)
+	}
+	t2 := 0.0
+	if maSlow > 0 {
+		t2 = (p.Close/maSlow - 1.0)
+	}
+	trend := 0.5*t1 + 0.5*t2
+
+	// Momentum
+	mom := math.NaN()
+	if i >= cfg.MomWindow && e.closes[i-cfg.MomWindow] != 0 {
+		mom = (p.Close / e.closes[i-cfg.MomWindow]) - 1.0
+	}
+
+	rsi := e.rsi(i)
+	macd := e.macd(i, p.Close)
+	vol := e.realizedVol(i)
+	mfi := e.mfi(i, p)
+	bb := e.percentB(i)
+	dd := e.drawdown(i)
+
+	raws := [numComponents]float64{trend, mom, rsi, macd, dd, vol, mfi, bb}
+	for c := range e.raw {
+		e.raw[c] = append(e.raw[c], raws[c])
+	}
+
+	res := models.ScoreResult{Date: p.Date, Price: p.Close}
+	res.Raw.Trend, res.Raw.Momentum, res.Raw.RSI, res.Raw.MACD = trend, mom, rsi, macd
+	res.Raw.Drawdown, res.Raw.Vol, res.Raw.MFI, res.Raw.BB = dd, vol, mfi, bb
+
+	n := i + 1
+	w := normWindowFor(n, cfg)
+	setValues(&res, e.rollingScores(i, w))
+	aggregate(&res, e.cfg.Weights)
+	quality(&res, &e.raw, i, w, cfg)
+	scheme := cfg.scheme()
+
+	// While history is shorter than the norm window Compute shrinks the window
+	// to the whole series, which leaves only the newest bar scored. Nothing
+	// before it was scored either, so labels and signal start over.
+	if i > 0 && w != normWindowFor(n-1, cfg) {
+		prev := &e.results[i-1]
+		setValues(prev, [numComponents]float64{math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN()})
+		aggregate(prev, e.cfg.Weights)
+		prev.Quality = models.DataQuality{}
+		e.st.lab, e.st.sm, e.st.reg = labeler{}, smoothState{}, regimer{}
+		e.st.reg.next(prev, DefaultRegime)
+		e.st.lab.label(prev, scheme, e.lang)
+		cfg.Smoothing.smooth(&e.st.sm, e.results, i-1)
+	}
+	e.st.reg.next(&res, DefaultRegime)
+	e.st.lab.label(&res, scheme, e.lang)
+	e.results = append(e.results, res)
+	cfg.Smoothing.smooth(&e.st.sm, e.results, i)
+	return e.results[i]
+}
+
+func setValues(res *models.ScoreResult, s [numComponents]float64) {
+	res.Values.Trend, res.Values.Momentum, res.Values.RSI, res.Values.MACD = s[0], s[1], s[2], s[3]
+	res.Values.Drawdown, res.Values.Vol, res.Values.MFI, res.Values.BB = s[4], s[5], s[6], s[7]
+}
+
+// sma mirrors SMA: running sum of the first window values, then slide.
+func sma(sum *float64, values []float64, window int) float64 {
+	i := len(values) - 1
+	if i < window {
+		*sum += values[i]
+	} else {
+		*sum = *sum - values[i-window] + values[i]
+	}
+	if i < window-1 {
+		return math.NaN()
+	}
+	return *sum / float64(window)
+}
+
+// rsi mirrors RSI: plain averages over the first window, Wilder after.
+func (e *Engine) rsi(i int) float64 {
+	if i == 0 {
+		return math.NaN()
+	}
+	window := e.cfg.RSIWindow
+	gain, loss := 0.0, 0.0
+	diff := e.closes[i] - e.closes[i-1]
+	if diff > 0 {
+		gain = diff
+	} else {
+		loss = -diff
+	}
+
+	st := &e.st
+	switch {
+	case i < window:
+		st.avgGain += gain
+		st.avgLoss += loss
+		return math.NaN()
+	case i == window:
+		st.avgGain += gain
+		st.avgLoss += loss
+		st.avgGain /= float64(window)
+		st.avgLoss /= float64(window)
+		rs := 0.0
+		if st.avgLoss != 0 {
+			rs = st.avgGain / st.avgLoss
+		}
+		if st.avgLoss == 0 && st.avgGain > 0 {
+			return 100
+		}
+		return 100 - (100 / (1 + rs))
+	}
+
+	st.avgGain = (st.avgGain*float64(window-1) + gain) / float64(window)
+	st.avgLoss = (st.avgLoss*float64(window-1) + loss) / float64(window)
+	rs := 0.0
+	if st.avgLoss != 0 {
+		rs = st.avgGain / st.avgLoss
+	} else if st.avgGain == 0 {
+		rs = 0
+	} else {
+		rs = 1e9 // Max
+	}
+	return 100 - (100 / (1 + rs))
+}
+
+// macd mirrors MACD: EMA 12/26 seeded with the first value, signal EMA 9.
+func (e *Engine) macd(i int, v float64) float64 {
+	st := &e.st
+	if i == 0 {
+		st.emaFast, st.emaSlow = v, v
+		st.emaSignal = st.emaFast - st.emaSlow
+		return (st.emaFast - st.emaSlow) - st.emaSignal
+	}
+	kFast, kSlow, kSignal := 2.0/float64(12+1), 2.0/float64(26+1), 2.0/float64(9+1)
+	st.emaFast = v*kFast + st.emaFast*(1-kFast)
+	st.emaSlow = v*kSlow + st.emaSlow*(1-kSlow)
+	line := st.emaFast - st.emaSlow
+	st.emaSignal = line*kSignal + st.emaSignal*(1-kSignal)
+	return line - st.emaSignal
+}
+
+// realizedVol mirrors RealizedVol, summing newest to oldest like the batch loop.
+func (e *Engine) realizedVol(i int) float64 {
+	ret := 0.0
+	if i > 0 && e.closes[i-1] != 0 {
+		ret = (e.closes[i] / e.closes[i-1]) - 1.0
+	}
+	e.returns = append(e.returns, ret)
+
+	window := e.cfg.VolWindow
+	if i < window {
+		return math.NaN()
+	}
+	sum := 0.0
+	for j := 0; j < window; j++ {
+		sum += e.returns[i-j]
+	}
+	mean := sum / float64(window)
+	sqSum := 0.0
+	for j := 0; j < window; j++ {
+		d := e.returns[i-j] - mean
+		sqSum += d * d
+	}
+	return math.Sqrt(sqSum/float64(window)) * math.Sqrt(252)
+}
+
+// mfi mirrors MFI(…, 14).
+func (e *Engine) mfi(i int, p models.Price) float64 {
+	const window = 14
+	st := &e.st
+	tp := (p.High + p.Low + p.Close) / 3.0
+	pos, neg := 0.0, 0.0
+	if i > 0 {
+		if tp > st.prevTP {
+			pos = tp * p.Volume
+		} else if tp < st.prevTP {
+			neg = tp * p.Volume
+		}
+	}
+	st.prevTP = tp
+	e.posFlow = append(e.posFlow, pos)
+	e.negFlow = append(e.negFlow, neg)
+
+	switch {
+	case i == 0:
+		return math.NaN()
+	case i <= window:
+		st.sumPos += pos
+		st.sumNeg += neg
+		if i < window {
+			return math.NaN()
+		}
+	default:
+		st.sumPos = st.sumPos - e.posFlow[i-window] + pos
+		st.sumNeg = st.sumNeg - e.negFlow[i-window] + neg
+	}
+
+	mfr := 0.0
+	if st.sumNeg != 0 {
+		mfr = st.sumPos / st.sumNeg
+	} else if st.sumPos > 0 {
+		mfr = 1e9 // Max
+	}
+	return 100.0 - (100.0 / (1.0 + mfr))
+}
+
+// percentB mirrors BollingerPercentB(…, 20, 2).
+func (e *Engine) percentB(i int) float64 {
+	const window, numStdDev = 20, 2.0
+	ma := sma(&e.st.bbMA, e.closes, window)
+	if i < window-1 {
+		return math.NaN()
+	}
+	sumSq := 0.0
+	for j := 0; j < window; j++ {
+		d := e.closes[i-j] - ma
+		sumSq += d * d
+	}
+	stdDev := math.Sqrt(sumSq / float64(window))
+	upper := ma + (stdDev * numStdDev)
+	lower := ma - (stdDev * numStdDev)
+	if upper != lower {
+		return (e.closes[i] - lower) / (upper - lower)
+	}
+	return 0.5
+}
+
+// drawdown keeps the rolling max as a monotonic deque instead of rescanning
+// the window.
+func (e *Engine) drawdown(i int) float64 {
+	st := &e.st
+	for len(st.ddMax) > 0 && e.closes[st.ddMax[len(st.ddMax)-1]] <= e.closes[i] {
+		st.ddMax = st.ddMax[:len(st.ddMax)-1]
+	}
+	st.ddMax = append(st.ddMax, i)
+	for st.ddMax[0] <= i-e.cfg.DDWindow {
+		st.ddMax = st.ddMax[1:]
+	}
+	maxP := e.closes[st.ddMax[0]]
+	if maxP > 0 {
+		return (e.closes[i] / maxP) - 1.0
+	}
+	return 0
+}
+
+// rollingScores mirrors RollingScore for the newest bar of every component.
+func (e *Engine) rollingScores(i, window int) [numComponents]float64 {
+	var out [numComponents]float64
+	for c := range out {
+		out[c] = math.NaN()
+		values := e.raw[c]
+		if math.IsNaN(values[i]) || i < window-1 {
+			continue
+		}
+		dir := directions[c]
+		current := values[i] * dir
+		valid, smaller := 0, 0
+		for j := i - window + 1; j <= i; j++ {
+			if math.IsNaN(values[j]) {
+				continue
+			}
+			valid++
+			if values[j]*dir <= current {
+				smaller++
+			}
+		}
+		if valid > 0 {
+			out[c] = float64(smaller) / float64(valid) * 100.0
+		}
+	}
+	return out
+}
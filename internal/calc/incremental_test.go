@@ -0,0 +1,129 @@
+package calc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/data/fake"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// bars is a deterministic daily series of n bars.
+func bars(t *testing.T, ticker string, n int) []models.Price {
+	t.Helper()
+	epoch := time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC)
+	end := epoch.AddDate(0, 0, n-1)
+	p := &fake.Provider{Now: func() time.Time { return end.Add(time.Hour) }}
+	pf, err := p.GetPrices(context.Background(), ticker, epoch, end, "1d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pf.Prices) != n {
+		t.Fatalf("got %d bars, want %d", len(pf.Prices), n)
+	}
+	return pf.Prices
+}
+
+// TestEngineMatchesCompute checks every prefix of the series, the ones shorter
+// than the norm window included: there the window grows with each bar and the
+// engine has to rescore the bars already pushed.
+func TestEngineMatchesCompute(t *testing.T) {
+	withNorm := func(w int) Config {
+		cfg := DefaultConfig
+		cfg.NormWindow = w
+		return cfg
+	}
+	with := func(cfg Config, f func(*Config)) Config {
+		f(&cfg)
+		return cfg
+	}
+	short := withNorm(40)
+
+	tests := []struct {
+		name string
+		cfg  Config
+		bars int
+	}{
+		{"default", DefaultConfig, 300},
+		{"short window", short, 160},
+		{"tiny window", withNorm(5), 80},
+		{"weights", with(short, func(c *Config) {
+			c.Weights = map[string]float64{"trend": 1, "rsi": 0.5, "macd": 0, "volatility": 2}
+		}), 160},
+		{"stable labels", with(short, func(c *Config) { c.Labels = "stable" }), 160},
+		{"three labels", with(short, func(c *Config) { c.Labels = "three" }), 160},
+		{"unknown labels", with(short, func(c *Config) { c.Labels = "nope" }), 60},
+		{"ema", with(short, func(c *Config) { c.Smoothing = Smoothing{Method: SmoothEMA, Period: 5} }), 160},
+		{"sma", with(short, func(c *Config) { c.Smoothing = Smoothing{Method: SmoothSMA, Period: 5} }), 160},
+		{"kalman", with(short, func(c *Config) { c.Smoothing = Smoothing{Method: SmoothKalman, Period: 5} }), 160},
+		{"stable labels ema", with(withNorm(252), func(c *Config) {
+			c.Labels = "stable"
+			c.Smoothing = Smoothing{Method: SmoothEMA, Period: 10}
+		}), 280},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices := bars(t, "AAPL", tt.bars)
+			e := NewEngine(tt.cfg, "en")
+			for n := 1; n <= len(prices); n++ {
+				p := prices[n-1]
+				got := e.Push(p)
+				if n%3 == 0 {
+					// A bar in progress: pushed with another close, then
+					// updated, sometimes more than once
+					moved := p
+					moved.Close *= 1.03
+					moved.High = max(moved.High, moved.Close)
+					e.Update(moved)
+					if n%2 == 0 {
+						moved.Close, moved.Low = p.Close*0.97, min(p.Low, p.Close*0.97)
+						e.Update(moved)
+					}
+					got = e.Update(p)
+				}
+
+				want := Compute(&models.PriceFrame{Prices: prices[:n]}, tt.cfg, "en")
+				if g, w := fmt.Sprintf("%+v", got), fmt.Sprintf("%+v", want[n-1]); g != w {
+					t.Fatalf("bar %d returned\n%s\nwant\n%s", n-1, g, w)
+				}
+				assertSame(t, fmt.Sprintf("prefix %d", n), e.Results(), want)
+			}
+
+			seeded := NewEngine(tt.cfg, "en")
+			seeded.Seed(prices)
+			assertSame(t, "seed", seeded.Results(), e.Results())
+		})
+	}
+}
+
+// TestEngineUpdateFirst covers Update on an empty engine and on a single bar,
+// which can't be undone.
+func TestEngineUpdateFirst(t *testing.T) {
+	prices := bars(t, "MSFT", 30)
+	e := NewEngine(DefaultConfig, "en")
+	e.Update(prices[0])
+	e.Update(prices[0])
+	if e.Len() != 1 {
+		t.Fatalf("Len() = %d after updating an empty engine, want 1", e.Len())
+	}
+	for _, p := range prices[1:] {
+		e.Push(p)
+	}
+	assertSame(t, "update first", e.Results(), Compute(&models.PriceFrame{Prices: prices}, DefaultConfig, "en"))
+}
+
+// assertSame compares results through their printed form, which tells NaNs
+// apart from numbers and spells out every float exactly.
+func assertSame(t *testing.T, what string, got, want []models.ScoreResult) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: %d results, want %d", what, len(got), len(want))
+	}
+	for i := range want {
+		if g, w := fmt.Sprintf("%+v", got[i]), fmt.Sprintf("%+v", want[i]); g != w {
+			t.Fatalf("%s: bar %d is\n%s\nwant\n%s", what, i, g, w)
+		}
+	}
+}
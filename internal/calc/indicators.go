@@ -124,6 +124,49 @@ func SMA(values []float64, window int) []float64 {
 	return out
 }
 
+// Trend: mean distance of the close from its fast and slow SMA
+func Trend(values []float64, fast, slow int) []float64 {
+	n := len(values)
+	maFast := SMA(values, fast)
+	maSlow := SMA(values, slow)
+	out := make([]float64, n)
+	for i := 0; i < n; i++ {
+		t1 := 0.0
+		if maFast[i] > 0 {
+			t1 = (values[i]/maFast[i] - 1.0)
+		}
+		t2 := 0.0
+		if maSlow[i] > 0 {
+			t2 = (values[i]/maSlow[i] - 1.0)
+		}
+		out[i] = 0.5*t1 + 0.5*t2
+	}
+	return out
+}
+
+// Drawdown from the highest close of the last window bars
+func Drawdown(values []float64, window int) []float64 {
+	n := len(values)
+	out := make([]float64, n)
+	// Rolling max
+	for i := 0; i < n; i++ {
+		start := i - window + 1
+		if start < 0 {
+			start = 0
+		}
+		maxP := 0.0
+		for j := start; j <= i; j++ {
+			if values[j] > maxP {
+				maxP = values[j]
+			}
+		}
+		if maxP > 0 {
+			out[i] = (values[i] / maxP) - 1.0
+		}
+	}
+	return out
+}
+
 // Momentum (Return over N periods)
 func Momentum(values []float64, window int) []float64 {
 	out := make([]float64, len(values))
@@ -170,12 +213,23 @@ func RSI(values []float64, window int) []float64 {
 	avgGain /= float64(window)
 	avgLoss /= float64(window)
 
+	// Initial value, from the first averages only so it never sees later bars
+	rs := 0.0
+	if avgLoss != 0 {
+		rs = avgGain / avgLoss
+	}
+	if avgLoss == 0 && avgGain > 0 {
+		out[window] = 100
+	} else {
+		out[window] = 100 - (100 / (1 + rs))
+	}
+
 	// Wilder's Smoothing
 	for i := window + 1; i < len(values); i++ {
 		avgGain = (avgGain*float64(window-1) + gains[i]) / float64(window)
 		avgLoss = (avgLoss*float64(window-1) + losses[i]) / float64(window)
 
-		rs := 0.0
+		rs = 0.0
 		if avgLoss != 0 {
 			rs = avgGain / avgLoss
 		} else if avgGain == 0 {
@@ -187,17 +241,6 @@ func RSI(values []float64, window int) []float64 {
 		out[i] = 100 - (100 / (1 + rs))
 	}
 
-	// Initial value
-	rs := 0.0
-	if avgLoss != 0 {
-		rs = avgGain / avgLoss
-	}
-	if avgLoss == 0 && avgGain > 0 {
-		out[window] = 100
-	} else {
-		out[window] = 100 - (100 / (1 + rs))
-	}
-
 	return out
 }
 
@@ -0,0 +1,260 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Built-in label schemes. "default" keeps the classic five bands without any
+// smoothing, 55 still neutral and 75 still greed, "stable" is the same bands
+// made sticky for alerting.
+var builtinSchemes = []models.LabelScheme{
+	{ID: "default", Bands: fiveBands},
+	{ID: "stable", Bands: fiveBands, Hysteresis: 3, MinDwell: 2},
+	{ID: "three", Bands: []models.LabelBand{
+		{Key: "fear", Upper: 40},
+		{Key: "neutral", Upper: 60},
+		{Key: "greed"},
+	}},
+}
+
+var fiveBands = []models.LabelBand{
+	{Key: "extreme_fear", Upper: 25},
+	{Key: "fear", Upper: 45},
+	{Key: "neutral", Upper: 55, Inclusive: true},
+	{Key: "greed", Upper: 75, Inclusive: true},
+	{Key: "extreme_greed"},
+}
+
+var (
+	labelSchemes  = indexSchemes(nil)
+	defaultScheme = "default"
+)
+
+func indexSchemes(custom []models.LabelScheme) map[string]models.LabelScheme {
+	m := make(map[string]models.LabelScheme, len(builtinSchemes)+len(custom))
+	for _, s := range builtinSchemes {
+		m[s.ID] = s
+	}
+	for _, s := range custom {
+		m[s.ID] = s
+	}
+	return m
+}
+
+// SetLabelSchemes adds the configured schemes to the built-in ones and picks
+// the one used when a request or Config names none. Validate them first.
+func SetLabelSchemes(custom []models.LabelScheme, def string) {
+	labelSchemes = indexSchemes(custom)
+	defaultScheme = def
+}
+
+// LookupScheme returns the scheme named id, the default one for "".
+func LookupScheme(id string) (models.LabelScheme, bool) {
+	if id == "" {
+		id = defaultScheme
+	}
+	s, ok := labelSchemes[id]
+	return s, ok
+}
+
+// SchemeIDs lists the known scheme ids, sorted.
+func SchemeIDs() []string {
+	ids := make([]string, 0, len(labelSchemes))
+	for id := range labelSchemes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// IsBuiltinScheme reports whether id names a built-in scheme.
+func IsBuiltinScheme(id string) bool {
+	for _, s := range builtinSchemes {
+		if s.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateScheme reports what is wrong with a configured scheme.
+func ValidateScheme(s models.LabelScheme) error {
+	var problems []string
+	if s.ID == "" {
+		problems = append(problems, "id required")
+	}
+	if len(s.Bands) < 2 {
+		problems = append(problems, "at least 2 bands required")
+	}
+	keys := map[string]bool{}
+	prev := 0.0
+	for i, b := range s.Bands {
+		if b.Key == "" || keys[b.Key] {
+			problems = append(problems, fmt.Sprintf("band %d: missing or duplicate key", i))
+		}
+		keys[b.Key] = true
+		if i == len(s.Bands)-1 {
+			break
+		}
+		if b.Upper <= prev || b.Upper >= 100 {
+			problems = append(problems, fmt.Sprintf("band %q: upper %g must rise within (0, 100)", b.Key, b.Upper))
+		}
+		prev = b.Upper
+	}
+	if s.Hysteresis < 0 || s.MinDwell < 0 {
+		problems = append(problems, "hysteresis and min_dwell must not be negative")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("label scheme %q: %s", s.ID, strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// bandOf is the band index of s without hysteresis.
+func bandOf(bands []models.LabelBand, s float64) int {
+	for i, b := range bands[:len(bands)-1] {
+		if upTo(b, s) {
+			return i
+		}
+	}
+	return len(bands) - 1
+}
+
+// upTo reports whether s is in b or a band below it.
+func upTo(b models.LabelBand, s float64) bool {
+	return s < b.Upper || b.Inclusive && s == b.Upper
+}
+
+// BandName is the name of band key of scheme in lang: the scheme's own name
+// for lang, else the catalog's label.<key>, else the scheme's name in one of
+// the fallbacks of lang, else the key itself. "" is NaN, named "-".
+func BandName(scheme models.LabelScheme, key, lang string) string {
+	if key == "" {
+		return "-"
+	}
+	var names map[string]string
+	for _, b := range scheme.Bands {
+		if b.Key == key {
+			names = b.Names
+			break
+		}
+	}
+	if n, ok := names[lang]; ok {
+		return n
+	}
+	if n := i18n.T(lang, "label."+key); n != "label."+key {
+		return n
+	}
+	if len(names) > 0 {
+		for _, l := range i18n.Chain(lang) {
+			if n, ok := names[l]; ok {
+				return n
+			}
+		}
+	}
+	return key
+}
+
+// DescribeScheme reports scheme with its band names in lang.
+func DescribeScheme(scheme models.LabelScheme, lang string) models.LabelSchemeInfo {
+	info := models.LabelSchemeInfo{
+		ID:         scheme.ID,
+		Hysteresis: scheme.Hysteresis,
+		MinDwell:   scheme.MinDwell,
+		Bands:      make([]models.BandRange, len(scheme.Bands)),
+	}
+	from := 0.0
+	for i, b := range scheme.Bands {
+		to := b.Upper
+		if i == len(scheme.Bands)-1 {
+			to = 100
+		}
+		info.Bands[i] = models.BandRange{Key: b.Key, Name: BandName(scheme, b.Key, lang), From: from, To: to,
+			Inclusive: b.Inclusive && i < len(scheme.Bands)-1}
+		from = to
+	}
+	return info
+}
+
+// LabelFromScore names the band of a single score in the default scheme,
+// "-" for NaN. Without the bars before it there is no hysteresis.
+func LabelFromScore(s float64, lang string) string {
+	scheme := Config{}.scheme()
+	if math.IsNaN(s) {
+		return "-"
+	}
+	return BandName(scheme, scheme.Bands[bandOf(scheme.Bands, s)].Key, lang)
+}
+
+// BandLabel names band key of the default scheme in lang, for a result
+// computed once and shown in several languages.
+func BandLabel(key, lang string) string {
+	return BandName(Config{}.scheme(), key, lang)
+}
+
+// labeler assigns bands bar by bar. The label only leaves its band once the
+// score clears the cut-off by the hysteresis margin, and has done so for
+// MinDwell bars in a row. The zero value has no band yet.
+type labeler struct {
+	band int // current band + 1, 0 before the first score
+	held int // bars in a row the score has been out of band
+}
+
+// next returns the band of s, given the bars before it, or -1 for NaN which
+// leaves the state alone.
+func (l *labeler) next(scheme models.LabelScheme, s float64) int {
+	if math.IsNaN(s) {
+		return -1
+	}
+	bands := scheme.Bands
+	raw := bandOf(bands, s)
+	if l.band == 0 {
+		l.band = raw + 1
+		return raw
+	}
+
+	cur, target := l.band-1, l.band-1
+	h := scheme.Hysteresis
+	switch {
+	case raw > cur:
+		// Highest band whose lower cut-off s clears by h
+		for b := raw; b > cur; b-- {
+			if !upTo(bands[b-1], s-h) {
+				target = b
+				break
+			}
+		}
+	case raw < cur:
+		for b := raw; b < cur; b++ {
+			if upTo(bands[b], s+h) {
+				target = b
+				break
+			}
+		}
+	}
+	if target == cur {
+		l.held = 0
+		return cur
+	}
+	if l.held++; l.held < scheme.MinDwell {
+		return cur
+	}
+	l.band, l.held = target+1, 0
+	return target
+}
+
+// label fills Label and Band of res through l.
+func (l *labeler) label(res *models.ScoreResult, scheme models.LabelScheme, lang string) {
+	if b := l.next(scheme, res.Score); b >= 0 {
+		res.Band = scheme.Bands[b].Key
+	} else {
+		res.Band = ""
+	}
+	res.Label = BandName(scheme, res.Band, lang)
+}
@@ -0,0 +1,96 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+// The five bands cut where LabelFromScore always did: 55 is still neutral and
+// 75 still greed.
+func TestBandCutoffs(t *testing.T) {
+	tests := []struct {
+		score float64
+		band  string
+		label string
+	}{
+		{0, "extreme_fear", "Extreme Fear"},
+		{24.9, "extreme_fear", "Extreme Fear"},
+		{25, "fear", "Fear"},
+		{44.99, "fear", "Fear"},
+		{45, "neutral", "Neutral"},
+		{55, "neutral", "Neutral"},
+		{55.01, "greed", "Greed"},
+		{75, "greed", "Greed"},
+		{75.01, "extreme_greed", "Extreme Greed"},
+		{100, "extreme_greed", "Extreme Greed"},
+	}
+	for _, id := range []string{"default", "stable"} {
+		scheme, ok := LookupScheme(id)
+		if !ok {
+			t.Fatalf("no %s scheme", id)
+		}
+		for _, tt := range tests {
+			// The first score has no band to stick to
+			var l labeler
+			if b := l.next(scheme, tt.score); scheme.Bands[b].Key != tt.band {
+				t.Errorf("%s: %g falls in %s, want %s", id, tt.score, scheme.Bands[b].Key, tt.band)
+			}
+		}
+	}
+	for _, tt := range tests {
+		if got := LabelFromScore(tt.score, "en"); got != tt.label {
+			t.Errorf("LabelFromScore(%g) = %q, want %q", tt.score, got, tt.label)
+		}
+	}
+	if got := LabelFromScore(math.NaN(), "en"); got != "-" {
+		t.Errorf("LabelFromScore(NaN) = %q, want -", got)
+	}
+}
+
+// TestStableLabels walks the stable scheme, 3 points of hysteresis and 2 bars
+// of dwell, across the neutral/greed cut-off at 55.
+func TestStableLabels(t *testing.T) {
+	scheme, _ := LookupScheme("stable")
+	steps := []struct {
+		score float64
+		band  string
+	}{
+		{50, "neutral"},
+		{58, "neutral"}, // 3 past 55, which is still neutral
+		{58.5, "neutral"},
+		{59, "greed"}, // second bar past the margin
+		{54, "greed"}, // back in neutral, but not 3 below
+		{52, "greed"},
+		{60, "greed"}, // back in band, the count starts over
+		{52, "greed"},
+		{math.NaN(), ""}, // leaves the count alone
+		{51, "neutral"},
+		{90, "neutral"}, // two bands up, still waits
+		{90, "extreme_greed"},
+		{76, "extreme_greed"},
+		{74, "extreme_greed"},
+		{71.9, "extreme_greed"},
+		{71, "greed"},
+	}
+	var l labeler
+	for i, st := range steps {
+		got := ""
+		if b := l.next(scheme, st.score); b >= 0 {
+			got = scheme.Bands[b].Key
+		}
+		if got != st.band {
+			t.Fatalf("step %d: %g labeled %q, want %q", i, st.score, got, st.band)
+		}
+	}
+}
+
+// Without hysteresis or dwell the label is the band of each score.
+func TestDefaultLabelsFollowScore(t *testing.T) {
+	scheme, _ := LookupScheme("default")
+	var l labeler
+	for _, s := range []float64{50, 56, 55, 75, 76, 24, 45, 90} {
+		if got, want := l.next(scheme, s), bandOf(scheme.Bands, s); got != want {
+			t.Errorf("%g: band %d, want %d", s, got, want)
+		}
+	}
+}
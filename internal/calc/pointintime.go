@@ -0,0 +1,42 @@
+package calc
+
+import (
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// ComputeAsOf returns the results the model would have shown at asof, using only
+// bars dated at or before it.
+func ComputeAsOf(pf *models.PriceFrame, cfg Config, lang string, asof time.Time) []models.ScoreResult {
+	return Compute(pf.Truncate(asof), cfg, lang)
+}
+
+// ComputePointInTime returns, for every bar in [start, end], the score as it would
+// have been published on that bar: computed from the bars up to and including it.
+// Zero bounds are open.
+//
+// Every indicator is causal, so once the history is long enough for the full
+// normalization window the batch result at i already equals the truncated one.
+// Only the early bars, where Compute shrinks normWindow to the history length,
+// need their own recomputation.
+func ComputePointInTime(pf *models.PriceFrame, cfg Config, lang string, start, end time.Time) []models.ScoreResult {
+	full := Compute(pf, cfg, lang)
+
+	out := make([]models.ScoreResult, 0, len(full))
+	for i, r := range full {
+		if !start.IsZero() && r.Date.Before(start) {
+			continue
+		}
+		if !end.IsZero() && r.Date.After(end) {
+			break
+		}
+		if i+1 < cfg.NormWindow {
+			prefix := &models.PriceFrame{Ticker: pf.Ticker, Frequency: pf.Frequency, Prices: pf.Prices[:i+1]}
+			part := Compute(prefix, cfg, lang)
+			r = part[len(part)-1]
+		}
+		out = append(out, r)
+	}
+	return out
+}
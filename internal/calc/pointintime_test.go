@@ -0,0 +1,53 @@
+package calc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// TestPointInTimeMatchesTruncated checks ComputePointInTime against the
+// definition, Compute on the history cut at each bar. The bars past the norm
+// window take the batch shortcut, the ones before it are recomputed.
+func TestPointInTimeMatchesTruncated(t *testing.T) {
+	withNorm := func(w int) Config {
+		cfg := DefaultConfig
+		cfg.NormWindow = w
+		return cfg
+	}
+	stable := withNorm(60)
+	stable.Labels = "stable"
+	stable.Smoothing = Smoothing{Method: SmoothKalman, Period: 5}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		bars int
+	}{
+		{"default", DefaultConfig, 320},
+		{"short window", withNorm(40), 160},
+		{"tiny window", withNorm(5), 60},
+		{"stable labels kalman", stable, 160},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices := bars(t, "AAPL", tt.bars)
+			pf := &models.PriceFrame{Ticker: "AAPL", Frequency: "1d", Prices: prices}
+			// Bounds inside the series, past one cut and before the other
+			start, end := prices[3].Date, prices[len(prices)-4].Date
+
+			got := ComputePointInTime(pf, tt.cfg, "en", start, end)
+			if want := len(prices) - 6; len(got) != want {
+				t.Fatalf("%d results, want %d", len(got), want)
+			}
+			for i, r := range got {
+				p := prices[i+3]
+				part := Compute(pf.Truncate(p.Date), tt.cfg, "en")
+				if g, w := fmt.Sprintf("%+v", r), fmt.Sprintf("%+v", part[len(part)-1]); g != w {
+					t.Fatalf("bar %d (%s) is\n%s\nwant\n%s", i+3, p.Date.Format("2006-01-02"), g, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package calc
+
+import (
+	"math"
+
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Market regimes. The same score reads differently in each: Extreme Fear in
+// a crisis is a crash, in a range it is a dip.
+const (
+	RegimeTrendingUp   = "trending_up"
+	RegimeTrendingDown = "trending_down"
+	RegimeRange        = "range"
+	RegimeCrisis       = "crisis"
+)
+
+// Regimes lists the regimes in display order.
+var Regimes = []string{RegimeTrendingUp, RegimeRange, RegimeTrendingDown, RegimeCrisis}
+
+// RegimeName is the name of regime key in lang, "-" for "".
+func RegimeName(key, lang string) string {
+	if key == "" {
+		return "-"
+	}
+	return i18n.T(lang, "regime."+key)
+}
+
+// RegimeConfig holds the cut-offs of the regime rules.
+type RegimeConfig struct {
+	CrisisVol      float64 // volatility percentile within the norm window, at or above
+	CrisisDrawdown float64 // drawdown sub-score at or below, i.e. among the deepest drawdowns
+	Trend          float64 // relative distance from the moving averages that makes a trend
+	MinDwell       int     // bars a new regime must hold before it takes over
+}
+
+var DefaultRegime = RegimeConfig{CrisisVol: 80, CrisisDrawdown: 20, Trend: 0.02, MinDwell: 3}
+
+// regimeOf classifies a bar from its raw trend and momentum and its
+// volatility and drawdown percentiles, "" until those are available.
+//
+//   - crisis: volatility in the top fifth of the window and the drawdown in
+//     its deepest fifth
+//   - trending up or down: close at least Trend away from its moving
+//     averages, with momentum agreeing
+//   - range: anything else
+func regimeOf(res models.ScoreResult, cfg RegimeConfig) string {
+	if math.IsNaN(res.Values.Vol) || math.IsNaN(res.Values.Drawdown) || math.IsNaN(res.Raw.Trend) {
+		return ""
+	}
+	// The volatility sub-score is inverted, calm is greedy
+	if 100-res.Values.Vol >= cfg.CrisisVol && res.Values.Drawdown <= cfg.CrisisDrawdown {
+		return RegimeCrisis
+	}
+	mom := res.Raw.Momentum
+	switch {
+	case res.Raw.Trend >= cfg.Trend && !(mom < 0):
+		return RegimeTrendingUp
+	case res.Raw.Trend <= -cfg.Trend && !(mom > 0):
+		return RegimeTrendingDown
+	}
+	return RegimeRange
+}
+
+// regimer tracks the regime bar by bar, switching only once the rules have
+// named the same new regime MinDwell bars in a row. The zero value has no
+// regime yet.
+type regimer struct {
+	cur, cand string
+	held      int
+}
+
+// next fills Regime of res.
+func (g *regimer) next(res *models.ScoreResult, cfg RegimeConfig) {
+	raw := regimeOf(*res, cfg)
+	switch {
+	case raw == "" || g.cur == "":
+		g.cur, g.cand, g.held = raw, "", 0
+	case raw == g.cur:
+		g.cand, g.held = "", 0
+	default:
+		if raw != g.cand {
+			g.cand, g.held = raw, 0
+		}
+		if g.held++; g.held >= cfg.MinDwell {
+			g.cur, g.cand, g.held = raw, "", 0
+		}
+	}
+	res.Regime = g.cur
+}
+
+// RegimeSpan is a run of bars in the same regime, indices into the series
+// inclusive.
+type RegimeSpan struct {
+	Regime     string
+	Start, End int
+}
+
+// RegimeSpans collapses the regime of every bar into runs, skipping bars
+// without one.
+func RegimeSpans(results []models.ScoreResult) []RegimeSpan {
+	var out []RegimeSpan
+	for i, r := range results {
+		if r.Regime == "" {
+			continue
+		}
+		if n := len(out); n > 0 && out[n-1].Regime == r.Regime && out[n-1].End == i-1 {
+			out[n-1].End = i
+			continue
+		}
+		out = append(out, RegimeSpan{Regime: r.Regime, Start: i, End: i})
+	}
+	return out
+}
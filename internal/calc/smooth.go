@@ -0,0 +1,122 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Smoothing methods of the signal line
+const (
+	SmoothNone   = "none"
+	SmoothEMA    = "ema"
+	SmoothSMA    = "sma"
+	SmoothKalman = "kalman"
+)
+
+// Crossover events between the score and its signal line
+const (
+	CrossUp   = "cross_up"   // score moved above the signal
+	CrossDown = "cross_down" // score moved below the signal
+)
+
+// Smoothing configures the signal line, a smoothed copy of the score.
+type Smoothing struct {
+	Method string // ema, sma or kalman; "" or none for no signal line
+	Period int    // bars; kalman settles at the gain of an EMA of this period
+}
+
+// Enabled reports whether s produces a signal line.
+func (s Smoothing) Enabled() bool {
+	return s.Method != "" && s.Method != SmoothNone
+}
+
+// Validate reports an unknown method or a period below 1.
+func (s Smoothing) Validate() error {
+	switch s.Method {
+	case "", SmoothNone:
+		return nil
+	case SmoothEMA, SmoothSMA, SmoothKalman:
+	default:
+		return fmt.Errorf("smoothing %q, expected none, ema, sma or kalman", s.Method)
+	}
+	if s.Period < 1 {
+		return fmt.Errorf("smoothing period %d must be positive", s.Period)
+	}
+	return nil
+}
+
+// smoothState carries the recursive filters from one bar to the next. The
+// zero value has seen no score yet.
+type smoothState struct {
+	started bool
+	level   float64
+	p       float64 // Kalman estimate variance, in units of the measurement noise
+}
+
+// signal is the signal line at bar i of results, whose scores up to i are
+// final. A NaN score gets a NaN signal and leaves the filters alone.
+func (s Smoothing) signal(st *smoothState, results []models.ScoreResult, i int) float64 {
+	x := results[i].Score
+	switch s.Method {
+	case SmoothSMA:
+		// Summed afresh every bar, NaN until the window holds only scores
+		if i+1 < s.Period {
+			return math.NaN()
+		}
+		sum := 0.0
+		for _, r := range results[i+1-s.Period : i+1] {
+			sum += r.Score
+		}
+		return sum / float64(s.Period)
+	case SmoothEMA, SmoothKalman:
+		if math.IsNaN(x) {
+			return math.NaN()
+		}
+		if !st.started {
+			st.started, st.level, st.p = true, x, 1
+			return x
+		}
+		alpha := 2 / float64(s.Period+1)
+		k := alpha
+		if s.Method == SmoothKalman {
+			// Local level model with the process noise picked so the gain
+			// settles at alpha: early bars follow the score more closely
+			st.p += alpha * alpha / (1 - alpha)
+			k = st.p / (st.p + 1)
+			st.p *= 1 - k
+		}
+		st.level += k * (x - st.level)
+		return st.level
+	}
+	return math.NaN()
+}
+
+// cross is the crossover event of bar cur after bar prev, "" when the score
+// stays on the same side of its signal or either is missing.
+func cross(prev, cur models.ScoreResult) string {
+	d0, d1 := prev.Score-prev.Signal, cur.Score-cur.Signal
+	switch {
+	case math.IsNaN(d0) || math.IsNaN(d1):
+		return ""
+	case d0 < 0 && d1 > 0:
+		return CrossUp
+	case d0 > 0 && d1 < 0:
+		return CrossDown
+	}
+	return ""
+}
+
+// smooth fills Signal and Cross of bar i.
+func (s Smoothing) smooth(st *smoothState, results []models.ScoreResult, i int) {
+	res := &results[i]
+	res.Signal, res.Cross = math.NaN(), ""
+	if !s.Enabled() {
+		return
+	}
+	res.Signal = s.signal(st, results, i)
+	if i > 0 {
+		res.Cross = cross(results[i-1], *res)
+	}
+}
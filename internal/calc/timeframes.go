@@ -0,0 +1,144 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Timeframes a fused score can blend. Weekly bars are built from daily ones.
+var Timeframes = []string{"1h", "1d", "1wk"}
+
+// TimeframeWeight is the share of one timeframe in a fused score.
+type TimeframeWeight struct {
+	Freq   string
+	Weight float64
+}
+
+// ParseTimeframes reads "1d:0.5,1wk:0.3,1h:0.2". A timeframe without a weight
+// weighs 1, weights don't need to add up to 1.
+func ParseTimeframes(s string) ([]TimeframeWeight, error) {
+	var out []TimeframeWeight
+	sum := 0.0
+	for _, part := range strings.Split(s, ",") {
+		freq, w, found := strings.Cut(strings.TrimSpace(part), ":")
+		tw := TimeframeWeight{Freq: freq, Weight: 1}
+		if found {
+			v, err := strconv.ParseFloat(w, 64)
+			if err != nil || v < 0 || math.IsInf(v, 0) {
+				return nil, fmt.Errorf("timeframe %q: weight must be a non-negative number", part)
+			}
+			tw.Weight = v
+		}
+		if !slices.Contains(Timeframes, freq) {
+			return nil, fmt.Errorf("timeframe %q, expected one of %s", freq, strings.Join(Timeframes, ", "))
+		}
+		if slices.ContainsFunc(out, func(t TimeframeWeight) bool { return t.Freq == freq }) {
+			return nil, fmt.Errorf("timeframe %q given twice", freq)
+		}
+		sum += tw.Weight
+		out = append(out, tw)
+	}
+	if sum <= 0 {
+		return nil, fmt.Errorf("timeframe weights add up to 0")
+	}
+	return out, nil
+}
+
+// FormatTimeframes is the inverse of ParseTimeframes.
+func FormatTimeframes(tfs []TimeframeWeight) string {
+	parts := make([]string, len(tfs))
+	for i, t := range tfs {
+		parts[i] = t.Freq + ":" + strconv.FormatFloat(t.Weight, 'g', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Stamped is a score as known from At on, the close of its bar.
+type Stamped struct {
+	At    time.Time
+	Score float64
+}
+
+// BarEnd is when the bar of freq starting at date closes.
+func BarEnd(date time.Time, freq string) time.Time {
+	switch freq {
+	case "1h":
+		return date.Add(time.Hour)
+	case "1wk":
+		return date.AddDate(0, 0, 7)
+	}
+	return date.AddDate(0, 0, 1)
+}
+
+// Stamp dates every score of results, bars of freq, with the close of its bar.
+func Stamp(results []models.ScoreResult, freq string) []Stamped {
+	out := make([]Stamped, len(results))
+	for i, r := range results {
+		out[i] = Stamped{At: BarEnd(r.Date, freq), Score: r.Score}
+	}
+	return out
+}
+
+// WeeklyAsOf scores weekly bars built from daily prices, once per day: on
+// each day the week in progress ends at that day's close, so the score is
+// the one that could have been known then.
+func WeeklyAsOf(prices []models.Price, cfg Config, lang string) []Stamped {
+	e := NewEngine(cfg, lang)
+	out := make([]Stamped, 0, len(prices))
+	var bar models.Price
+	var week int
+	for i, p := range prices {
+		y, w := p.Date.ISOWeek()
+		if i == 0 || y*100+w != week {
+			week, bar = y*100+w, p
+			e.Push(bar)
+		} else {
+			bar.High = math.Max(bar.High, p.High)
+			bar.Low = math.Min(bar.Low, p.Low)
+			bar.Close = p.Close
+			bar.Volume += p.Volume
+			e.Update(bar)
+		}
+		last, _ := e.Last()
+		out = append(out, Stamped{At: BarEnd(p.Date, "1d"), Score: last.Score})
+	}
+	return out
+}
+
+// Align is, for every time of at, the newest score of src known by then,
+// NaN when there is none. Both must be sorted.
+func Align(at []time.Time, src []Stamped) []float64 {
+	out := make([]float64, len(at))
+	j := -1
+	for i, t := range at {
+		for j+1 < len(src) && !src[j+1].At.After(t) {
+			j++
+		}
+		out[i] = math.NaN()
+		if j >= 0 {
+			out[i] = src[j].Score
+		}
+	}
+	return out
+}
+
+// Fuse is the weighted mean of the scores that aren't NaN, NaN when none is.
+func Fuse(scores []float64, tfs []TimeframeWeight) float64 {
+	sum, wSum := 0.0, 0.0
+	for i, s := range scores {
+		if !math.IsNaN(s) && tfs[i].Weight > 0 {
+			sum += s * tfs[i].Weight
+			wSum += tfs[i].Weight
+		}
+	}
+	if wSum == 0 {
+		return math.NaN()
+	}
+	return sum / wSum
+}
@@ -0,0 +1,116 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+)
+
+// Exchange describes when a market opens and closes. Holidays are not modelled: on
+// a holiday the latest bar is still the previous session, which is already archived,
+// so the run is a no-op. Lunch breaks are not modelled either.
+type Exchange struct {
+	Name        string
+	Location    *time.Location
+	OpenHour    int
+	OpenMinute  int
+	CloseHour   int
+	CloseMinute int
+	Weekends    bool // trades 7 days a week (crypto)
+}
+
+var (
+	NYSE   = Exchange{Name: "NYSE", Location: mustLoad("America/New_York"), OpenHour: 9, OpenMinute: 30, CloseHour: 16}
+	HKEX   = Exchange{Name: "HKEX", Location: mustLoad("Asia/Hong_Kong"), OpenHour: 9, OpenMinute: 30, CloseHour: 16, CloseMinute: 10}
+	SSE    = Exchange{Name: "SSE", Location: mustLoad("Asia/Shanghai"), OpenHour: 9, OpenMinute: 30, CloseHour: 15}
+	Crypto = Exchange{Name: "CRYPTO", Location: time.UTC, CloseHour: 0, Weekends: true} // open equals close: around the clock
+)
+
+func mustLoad(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// ExchangeFor guesses the exchange from the Yahoo ticker suffix.
+func ExchangeFor(ticker string) Exchange {
+	t := strings.ToUpper(ticker)
+	switch {
+	case strings.HasSuffix(t, ".HK"), t == "^HSI":
+		return HKEX
+	case strings.HasSuffix(t, ".SS"), strings.HasSuffix(t, ".SZ"):
+		return SSE
+	case strings.HasSuffix(t, "-USD"), strings.HasSuffix(t, "-USDT"):
+		return Crypto
+	default:
+		return NYSE
+	}
+}
+
+func (e Exchange) tradingDay(d time.Time) bool {
+	if e.Weekends {
+		return true
+	}
+	wd := d.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// NextClose returns the first session close strictly after t.
+func (e Exchange) NextClose(t time.Time) time.Time {
+	return e.next(t, e.CloseHour, e.CloseMinute)
+}
+
+// NextOpen returns the first session open strictly after t.
+func (e Exchange) NextOpen(t time.Time) time.Time {
+	return e.next(t, e.OpenHour, e.OpenMinute)
+}
+
+// IsOpen reports whether t falls inside a session.
+func (e Exchange) IsOpen(t time.Time) bool {
+	local := t.In(e.Location)
+	if !e.tradingDay(local) {
+		return false
+	}
+	if e.OpenHour == e.CloseHour && e.OpenMinute == e.CloseMinute {
+		return true
+	}
+	open := time.Date(local.Year(), local.Month(), local.Day(), e.OpenHour, e.OpenMinute, 0, 0, e.Location)
+	close := time.Date(local.Year(), local.Month(), local.Day(), e.CloseHour, e.CloseMinute, 0, 0, e.Location)
+	return !local.Before(open) && local.Before(close)
+}
+
+func (e Exchange) next(t time.Time, hour, minute int) time.Time {
+	local := t.In(e.Location)
+	d := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, e.Location)
+	for !d.After(t) || !e.tradingDay(d) {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// SessionsBetween counts the trading days strictly between the exchange-local
+// days of a and b, a before b.
+func (e Exchange) SessionsBetween(a, b time.Time) int {
+	a, b = a.In(e.Location), b.In(e.Location)
+	d := time.Date(a.Year(), a.Month(), a.Day(), 12, 0, 0, 0, e.Location).AddDate(0, 0, 1)
+	last := time.Date(b.Year(), b.Month(), b.Day(), 12, 0, 0, 0, e.Location)
+	n := 0
+	for ; d.Before(last); d = d.AddDate(0, 0, 1) {
+		if e.tradingDay(d) {
+			n++
+		}
+	}
+	return n
+}
+
+// FormatBarTime renders daily bars as exchange-local dates and intraday bars as
+// RFC 3339 timestamps with the exchange's offset, so hourly bars of the same day
+// stay distinct.
+func FormatBarTime(t time.Time, freq string, loc *time.Location) string {
+	t = t.In(loc)
+	if freq == "1d" || freq == "1wk" || freq == "1mo" {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
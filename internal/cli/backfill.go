@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/barstore"
+)
+
+func runBackfill(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	freq := fs.String("freq", "1d", "bar frequency, 1d or 1h")
+	var dates dateRange
+	dates.register(fs, "first day, YYYY-MM-DD (default the provider's default range)")
+	cfg, tickers, err := setup(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := dates.check(fs); err != nil {
+		return err
+	}
+	if cfg.Data.BarStoreDir == "" {
+		return errors.New("no bar store, set -bar-store-dir or BAR_STORE_DIR")
+	}
+	store, err := barstore.Open(cfg.Data.BarStoreDir)
+	if err != nil {
+		return fmt.Errorf("open bar store: %w", err)
+	}
+	// Record directly rather than through a Recorder, to report what was new
+	p := NewProvider(cfg)
+
+	return forEach(tickers, func(ticker string) error {
+		start, end, err := dates.bounds(ticker)
+		if err != nil {
+			return err
+		}
+		pf, err := fetch(ctx, cfg, p, ticker, start, end, *freq)
+		if err != nil {
+			return err
+		}
+		n, err := store.Record(pf, time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%s %s: %d bars, %d new revisions\n", ticker, *freq, len(pf.Prices), n)
+		return nil
+	})
+}
@@ -0,0 +1,214 @@
+// Package cli holds the offline subcommands of the server binary: score,
+// history, backfill and validate. They load the same config and go through the
+// same provider and calc.Compute as the web server, so cron jobs and notebooks
+// get identical numbers without standing one up.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/barstore"
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/config"
+	"github.com/iwanlebron/stock-analysis/internal/data"
+	"github.com/iwanlebron/stock-analysis/internal/data/fake"
+	"github.com/iwanlebron/stock-analysis/internal/logging"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+type command struct {
+	name, summary string
+	run           func(ctx context.Context, args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"serve", "run the web server (default)", nil},
+	{"score", "print the latest score and sub-scores", runScore},
+	{"history", "write the score series as csv, xlsx or ndjson", runHistory},
+	{"backfill", "fetch bars into the bar store", runBackfill},
+	{"validate", "check bars for gaps and bad values", runValidate},
+}
+
+// errUsage makes Run exit with status 2, the reason is already printed.
+var errUsage = errors.New("usage")
+
+// IsCommand reports whether name is a subcommand other than serve.
+func IsCommand(name string) bool {
+	for _, c := range commands {
+		if c.name == name && c.run != nil {
+			return true
+		}
+	}
+	return name == "help"
+}
+
+// Run runs subcommand name and returns the exit status.
+func Run(name string, args []string) int {
+	if name == "help" {
+		Usage(os.Stdout)
+		return 0
+	}
+	for _, c := range commands {
+		if c.name != name || c.run == nil {
+			continue
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		err := c.run(ctx, args, os.Stdout)
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	Usage(os.Stderr)
+	return 2
+}
+
+// Usage lists the subcommands.
+func Usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [args]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for the flags of a command. Every command also takes the\nserver's config flags and env vars (-config, -data-provider, -window, -lang...).\n", os.Args[0])
+}
+
+// setup parses args on fs, which holds the subcommand's own flags, and
+// installs the logger. At least one ticker is required.
+func setup(fs *flag.FlagSet, args []string) (config.Config, []string, error) {
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] TICKER...\n\nFlags:\n", os.Args[0], fs.Name())
+		fs.PrintDefaults()
+	}
+	cfg, tickers, err := config.Parse(fs, args, os.Getenv)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return cfg, nil, err
+		}
+		fmt.Fprintln(fs.Output(), err)
+		return cfg, nil, errUsage
+	}
+	if len(tickers) == 0 {
+		fmt.Fprintln(fs.Output(), "at least one ticker is required")
+		fs.Usage()
+		return cfg, nil, errUsage
+	}
+	for i, t := range tickers {
+		tickers[i] = strings.ToUpper(t)
+	}
+	logging.Setup(os.Stderr, cfg.Log.Format, cfg.Log.Level)
+	calc.SetLabelSchemes(cfg.Score.LabelSchemes, cfg.Score.Labels)
+	return cfg, tickers, nil
+}
+
+// NewProvider is the bare data provider selected by cfg.Data.Provider, without
+// metrics, caching or the bar store.
+func NewProvider(cfg config.Config) models.Provider {
+	if cfg.Data.Provider == "fake" {
+		// Synthetic bars, for demos and trying things offline
+		return &fake.Provider{}
+	}
+	return data.NewYahooProvider()
+}
+
+// provider is NewProvider recording into the bar store when one is configured,
+// like the server does.
+func provider(cfg config.Config) (models.Provider, error) {
+	p := NewProvider(cfg)
+	if dir := cfg.Data.BarStoreDir; dir != "" {
+		store, err := barstore.Open(dir)
+		if err != nil {
+			return nil, fmt.Errorf("open bar store: %w", err)
+		}
+		p = &barstore.Recorder{Upstream: p, Store: store}
+	}
+	return p, nil
+}
+
+// dateRange holds the -start and -end flags. Days are exchange-local, as in
+// the API.
+type dateRange struct {
+	start, end string
+}
+
+func (d *dateRange) register(fs *flag.FlagSet, startUsage string) {
+	fs.StringVar(&d.start, "start", "", startUsage)
+	fs.StringVar(&d.end, "end", "", "last day, YYYY-MM-DD (default today)")
+}
+
+// check reports malformed dates before anything is fetched.
+func (d dateRange) check(fs *flag.FlagSet) error {
+	if _, _, err := d.bounds(""); err != nil {
+		fmt.Fprintln(fs.Output(), err)
+		return errUsage
+	}
+	return nil
+}
+
+// bounds resolves the range for ticker. Zero times are open.
+func (d dateRange) bounds(ticker string) (start, end time.Time, err error) {
+	loc := calendar.ExchangeFor(ticker).Location
+	if d.start != "" {
+		if start, err = time.ParseInLocation("2006-01-02", d.start, loc); err != nil {
+			return start, end, fmt.Errorf("invalid -start %q, expected YYYY-MM-DD", d.start)
+		}
+	}
+	if d.end != "" {
+		if end, err = time.ParseInLocation("2006-01-02", d.end, loc); err != nil {
+			return start, end, fmt.Errorf("invalid -end %q, expected YYYY-MM-DD", d.end)
+		}
+		end = end.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if !start.IsZero() && end.Before(start) {
+			return start, end, errors.New("-end must not be before -start")
+		}
+	}
+	return start, end, nil
+}
+
+// fetch loads ticker's bars within cfg.Score.FetchTimeout.
+func fetch(ctx context.Context, cfg config.Config, p models.Provider, ticker string, start, end time.Time, freq string) (*models.PriceFrame, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Score.FetchTimeout.D())
+	defer cancel()
+	pf, err := p.GetPrices(ctx, ticker, start, end, freq)
+	if err != nil {
+		return nil, err
+	}
+	if len(pf.Prices) == 0 {
+		return nil, fmt.Errorf("no data for %s", ticker)
+	}
+	return pf, nil
+}
+
+// forEach runs fn for every ticker, reporting failures on stderr and carrying
+// on with the rest.
+func forEach(tickers []string, fn func(ticker string) error) error {
+	failed := 0
+	for _, t := range tickers {
+		if err := fn(t); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "%s: %v\n", t, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tickers failed", failed, len(tickers))
+	}
+	return nil
+}
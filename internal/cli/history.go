@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/export"
+)
+
+func runHistory(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	freq := fs.String("freq", "1d", "bar frequency, 1d or 1h")
+	format := fs.String("format", export.CSV, "output format, csv, xlsx or ndjson")
+	out := fs.String("o", "", "output file (default stdout)")
+	var dates dateRange
+	dates.register(fs, "first day, YYYY-MM-DD (default the last -tail bars)")
+	cfg, tickers, err := setup(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := dates.check(fs); err != nil {
+		return err
+	}
+	if _, ok := export.ContentTypes[*format]; !ok {
+		fmt.Fprintf(fs.Output(), "invalid -format %q, expected csv, xlsx or ndjson\n", *format)
+		return errUsage
+	}
+	p, err := provider(cfg)
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	var f *os.File
+	if *out != "" {
+		if f, err = os.Create(*out); err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	ew, err := export.New(*format, w, cfg.Score.Lang)
+	if err != nil {
+		return err
+	}
+
+	calcCfg := calc.DefaultConfig
+	calcCfg.NormWindow = cfg.Score.Window
+
+	err = forEach(tickers, func(ticker string) error {
+		start, end, err := dates.bounds(ticker)
+		if err != nil {
+			return err
+		}
+		pf, err := fetch(ctx, cfg, p, ticker, calc.WarmupStart(start, *freq), end, *freq)
+		if err != nil {
+			return err
+		}
+		results := calc.Compute(pf, calcCfg, cfg.Score.Lang)
+
+		// Drop the warmup bars, like the API does
+		from := max(len(results)-cfg.Score.Tail, 0)
+		if !start.IsZero() {
+			from = len(results)
+			for i, r := range results {
+				if !r.Date.Before(start) {
+					from = i
+					break
+				}
+			}
+		}
+		loc := calendar.ExchangeFor(ticker).Location
+		for _, r := range results[from:] {
+			row := export.Row{Ticker: ticker, Date: calendar.FormatBarTime(r.Date, *freq, loc), Result: r}
+			if err := ew.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if cerr := ew.Close(); cerr != nil {
+		return cerr
+	}
+	if f != nil {
+		if cerr := f.Close(); cerr != nil {
+			return cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// subscoreOrder is the column order of the score table.
+var subscoreOrder = []string{"trend", "momentum", "rsi", "macd", "drawdown", "volatility", "mfi", "bb_pct_b"}
+
+func runScore(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("score", flag.ContinueOnError)
+	freq := fs.String("freq", "1d", "bar frequency, 1d or 1h")
+	format := fs.String("format", "table", "output format, table or json (one object per line)")
+	cfg, tickers, err := setup(fs, args)
+	if err != nil {
+		return err
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(fs.Output(), "invalid -format %q, expected table or json\n", *format)
+		return errUsage
+	}
+	p, err := provider(cfg)
+	if err != nil {
+		return err
+	}
+
+	calcCfg := calc.DefaultConfig
+	calcCfg.NormWindow = cfg.Score.Window
+
+	var events []models.StreamEvent
+	err = forEach(tickers, func(ticker string) error {
+		pf, err := fetch(ctx, cfg, p, ticker, time.Time{}, time.Time{}, *freq)
+		if err != nil {
+			return err
+		}
+		results := calc.Compute(pf, calcCfg, cfg.Score.Lang)
+		events = append(events, latestEvent(ticker, *freq, results[len(results)-1]))
+		return nil
+	})
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		for _, ev := range events {
+			if err := enc.Encode(ev); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+	if len(events) > 0 {
+		tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TICKER\tDATE\tPRICE\tSCORE\tLABEL\tCONFIDENCE\t"+strings.Join(subscoreOrder, "\t"))
+		for _, ev := range events {
+			cells := []string{ev.Ticker, ev.Date, fmt.Sprintf("%.2f", ev.Price), number(ev.Score), ev.Label, cmp.Or(ev.Confidence, "-")}
+			for _, k := range subscoreOrder {
+				if v, ok := ev.Subscores[k]; ok {
+					cells = append(cells, number(&v))
+				} else {
+					cells = append(cells, "-")
+				}
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		if ferr := tw.Flush(); ferr != nil {
+			return ferr
+		}
+	}
+	return err
+}
+
+// latestEvent shapes r like a stream event, with NaN scores left out.
+func latestEvent(ticker, freq string, r models.ScoreResult) models.StreamEvent {
+	ev := models.StreamEvent{
+		Ticker:    ticker,
+		Frequency: freq,
+		Date:      calendar.FormatBarTime(r.Date, freq, calendar.ExchangeFor(ticker).Location),
+		Label:     r.Label,
+		Price:     r.Price,
+	}
+	if !math.IsNaN(r.Score) {
+		v := r.Score
+		ev.Score = &v
+		ev.Confidence = r.Quality.Confidence
+		ev.Subscores = make(map[string]float64)
+		for k, v := range r.Subscores() {
+			if !math.IsNaN(v) {
+				ev.Subscores[k] = v
+			}
+		}
+	}
+	return ev
+}
+
+func number(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", *v)
+}
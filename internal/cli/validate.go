@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Thresholds of the validate warnings
+const (
+	maxMissingSessions = 5   // longer gaps than a Golden Week are suspicious
+	maxDailyMove       = 0.4 // close to close, usually an unadjusted split
+	staleAfter         = 7 * 24 * time.Hour
+)
+
+// issue is one problem found in a ticker's bars. Errors make validate fail,
+// warnings only with -strict.
+type issue struct {
+	date  time.Time // zero for series-wide issues
+	level string    // "error" or "warn"
+	msg   string
+}
+
+func runValidate(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	freq := fs.String("freq", "1d", "bar frequency, 1d or 1h")
+	strict := fs.Bool("strict", false, "fail on warnings too")
+	var dates dateRange
+	dates.register(fs, "first day, YYYY-MM-DD (default the provider's default range)")
+	cfg, tickers, err := setup(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := dates.check(fs); err != nil {
+		return err
+	}
+	p, err := provider(cfg)
+	if err != nil {
+		return err
+	}
+
+	calcCfg := calc.DefaultConfig
+	calcCfg.NormWindow = cfg.Score.Window
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+	return forEach(tickers, func(ticker string) error {
+		start, end, err := dates.bounds(ticker)
+		if err != nil {
+			return err
+		}
+		pf, err := fetch(ctx, cfg, p, ticker, start, end, *freq)
+		if err != nil {
+			return err
+		}
+		asof := time.Now()
+		if !end.IsZero() && end.Before(asof) {
+			asof = end
+		}
+		ex := calendar.ExchangeFor(ticker)
+		issues := checkBars(pf, ex, asof)
+
+		// Enough history for the latest bar to get a score?
+		results := calc.Compute(pf, calcCfg, cfg.Score.Lang)
+		if last := results[len(results)-1]; math.IsNaN(last.Score) {
+			issues = append(issues, issue{last.Date, "warn", fmt.Sprintf("no score for the latest bar, %d bars are not enough to warm up", len(pf.Prices))})
+		}
+
+		errs, warns := 0, 0
+		for _, is := range issues {
+			date := "-"
+			if !is.date.IsZero() {
+				date = calendar.FormatBarTime(is.date, *freq, ex.Location)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ticker, *freq, date, is.level, is.msg)
+			if is.level == "error" {
+				errs++
+			} else {
+				warns++
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d bars\t%d errors\t%d warnings\n", ticker, *freq, len(pf.Prices), errs, warns)
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if errs > 0 || *strict && warns > 0 {
+			return fmt.Errorf("%d errors, %d warnings", errs, warns)
+		}
+		return nil
+	})
+}
+
+// checkBars looks for bars the indicators would choke on or silently get wrong.
+func checkBars(pf *models.PriceFrame, ex calendar.Exchange, asof time.Time) []issue {
+	var issues []issue
+	add := func(date time.Time, level, format string, args ...any) {
+		issues = append(issues, issue{date, level, fmt.Sprintf(format, args...)})
+	}
+	daily := pf.Frequency == "1d"
+	zeroVolume := 0
+	for i, b := range pf.Prices {
+		if i > 0 {
+			prev := pf.Prices[i-1]
+			switch {
+			case b.Date.Equal(prev.Date):
+				add(b.Date, "error", "duplicate bar")
+			case b.Date.Before(prev.Date):
+				add(b.Date, "error", "out of order, after %s", prev.Date.Format(time.RFC3339))
+			default:
+				if n := ex.SessionsBetween(prev.Date, b.Date); n > maxMissingSessions {
+					add(b.Date, "warn", "gap of %d sessions since the previous bar", n)
+				}
+			}
+			if daily && prev.Close > 0 && b.Close > 0 {
+				if move := b.Close/prev.Close - 1; math.Abs(move) > maxDailyMove {
+					add(b.Date, "warn", "close moved %+.0f%%, unadjusted split?", move*100)
+				}
+			}
+		}
+
+		var bad []string
+		for _, f := range []struct {
+			name string
+			v    float64
+		}{{"open", b.Open}, {"high", b.High}, {"low", b.Low}, {"close", b.Close}} {
+			if math.IsNaN(f.v) || math.IsInf(f.v, 0) || f.v <= 0 {
+				bad = append(bad, fmt.Sprintf("%s=%g", f.name, f.v))
+			}
+		}
+		if len(bad) > 0 {
+			add(b.Date, "error", "invalid price %s", strings.Join(bad, " "))
+			continue
+		}
+		switch {
+		case b.High < b.Low:
+			add(b.Date, "error", "high %g below low %g", b.High, b.Low)
+		case b.High < max(b.Open, b.Close) || b.Low > min(b.Open, b.Close):
+			add(b.Date, "error", "open or close outside high-low range")
+		}
+		switch {
+		case math.IsNaN(b.Volume) || b.Volume < 0:
+			add(b.Date, "error", "invalid volume %g", b.Volume)
+		case b.Volume == 0:
+			zeroVolume++
+		}
+	}
+
+	// Indices have no volume, everything else needs it for MFI
+	if zeroVolume > 0 && !strings.HasPrefix(pf.Ticker, "^") {
+		add(time.Time{}, "warn", "%d bars without volume", zeroVolume)
+	}
+	if n := len(pf.Prices); n > 0 {
+		if last := pf.Prices[n-1].Date; asof.Sub(last) > staleAfter {
+			add(last, "warn", "latest bar is %d days old", int(asof.Sub(last).Hours()/24))
+		}
+	}
+	return issues
+}
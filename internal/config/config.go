@@ -0,0 +1,347 @@
+// Package config is the typed server configuration. Settings are layered:
+// built-in defaults, then a JSON or YAML file, then environment variables, then
+// command line flags, each overriding the one before.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Config holds every tunable of the server. The env and flag tags name the
+// overrides; secret fields are masked by Public.
+type Config struct {
+	Server    Server    `json:"server"`
+	Data      Data      `json:"data"`
+	Cache     Cache     `json:"cache"`
+	RateLimit RateLimit `json:"rate_limit"`
+	Score     Score     `json:"score"`
+	Stream    Stream    `json:"stream"`
+	Archive   Archive   `json:"archive"`
+	Log       Log       `json:"log"`
+	Health    Health    `json:"health"`
+	Admin     Admin     `json:"admin"`
+}
+
+type Server struct {
+	Port              int      `json:"port" env:"PORT" flag:"port" usage:"listen port"`
+	GRPCPort          int      `json:"grpc_port" env:"GRPC_PORT" flag:"grpc-port" usage:"gRPC listen port, 0 disables"`
+	ReadHeaderTimeout Duration `json:"read_header_timeout" env:"READ_HEADER_TIMEOUT" flag:"read-header-timeout" usage:"time to read request headers"`
+	ReadTimeout       Duration `json:"read_timeout" env:"READ_TIMEOUT" flag:"read-timeout" usage:"time to read a whole request"`
+	WriteTimeout      Duration `json:"write_timeout" env:"WRITE_TIMEOUT" flag:"write-timeout" usage:"time to write a response (batches and streams extend it)"`
+	IdleTimeout       Duration `json:"idle_timeout" env:"IDLE_TIMEOUT" flag:"idle-timeout" usage:"keep-alive idle timeout"`
+	ShutdownDelay     Duration `json:"shutdown_delay" env:"SHUTDOWN_DELAY" flag:"shutdown-delay" usage:"on SIGTERM, report not ready for this long before closing the listener"`
+	ShutdownTimeout   Duration `json:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" usage:"how long to drain in-flight requests and jobs on shutdown"`
+}
+
+type Data struct {
+	Provider    string `json:"provider" env:"DATA_PROVIDER" flag:"data-provider" usage:"yahoo or fake"`
+	BarStoreDir string `json:"bar_store_dir" env:"BAR_STORE_DIR" flag:"bar-store-dir" usage:"keep every bar revision here, empty disables"`
+}
+
+type Cache struct {
+	Backend         string   `json:"backend" env:"CACHE_BACKEND" flag:"cache-backend" usage:"memory or redis"`
+	RedisURL        string   `json:"redis_url" env:"REDIS_URL" flag:"redis-url" secret:"true" usage:"redis://[user:password@]host[:port][/db] for the redis backend"`
+	Prefix          string   `json:"prefix" env:"CACHE_PREFIX" flag:"cache-prefix" usage:"key prefix on a shared redis"`
+	TTL             Duration `json:"ttl" env:"CACHE_TTL" flag:"cache-ttl" usage:"score cache TTL"`
+	BarsTTL         Duration `json:"bars_ttl" env:"CACHE_BARS_TTL" flag:"cache-bars-ttl" usage:"raw bar cache TTL, also how stale live streams may get"`
+	CleanupInterval Duration `json:"cleanup_interval" env:"CACHE_CLEANUP_INTERVAL" flag:"cache-cleanup-interval" usage:"how often expired entries are purged (memory backend)"`
+}
+
+// RateLimit charges requests to a token bucket per client: callers without a
+// key by IP, callers with one by key. A batch costs one token per ticker.
+type RateLimit struct {
+	PerMinute      int      `json:"per_minute" env:"RATE_LIMIT_PER_MINUTE" flag:"rate-limit" usage:"tokens per minute per IP without an API key, 0 disables"`
+	Burst          int      `json:"burst" env:"RATE_LIMIT_BURST" flag:"rate-limit-burst" usage:"bucket size without an API key, 0 means per_minute"`
+	DailyQuota     int      `json:"daily_quota" env:"RATE_LIMIT_DAILY_QUOTA" flag:"daily-quota" usage:"tokens per IP per UTC day without an API key, 0 unlimited"`
+	RequireKey     bool     `json:"require_key" env:"REQUIRE_API_KEY" flag:"require-api-key" usage:"reject API requests without a key"`
+	Keys           APIKeys  `json:"keys" env:"API_KEYS" secret:"true" usage:"comma separated name:key pairs"`
+	KeyPerMinute   int      `json:"key_per_minute" env:"API_KEY_PER_MINUTE" flag:"api-key-rate-limit" usage:"default tokens per minute of a key, 0 unlimited"`
+	KeyDailyQuota  int      `json:"key_daily_quota" env:"API_KEY_DAILY_QUOTA" flag:"api-key-daily-quota" usage:"default tokens per UTC day of a key, 0 unlimited"`
+	TrustedProxies []string `json:"trusted_proxies" env:"TRUSTED_PROXIES" flag:"trusted-proxies" usage:"comma separated proxy IPs or CIDRs whose X-Forwarded-For is believed"`
+}
+
+// APIKey is one client allowed to call the API. Zero limits fall back to the
+// key_* defaults of RateLimit.
+type APIKey struct {
+	Name       string `json:"name"`
+	Key        string `json:"key"`
+	PerMinute  int    `json:"per_minute,omitempty"`
+	Burst      int    `json:"burst,omitempty"`
+	DailyQuota int    `json:"daily_quota,omitempty"`
+}
+
+type APIKeys []APIKey
+
+// Set parses the API_KEYS form, "name:key,name:key".
+func (ks *APIKeys) Set(s string) error {
+	var out APIKeys
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(item, ":")
+		if !ok {
+			return fmt.Errorf("API key %q, expected name:key", name)
+		}
+		out = append(out, APIKey{Name: name, Key: key})
+	}
+	*ks = out
+	return nil
+}
+
+func (ks APIKeys) mask() interface{} {
+	out := make(APIKeys, len(ks))
+	for i, k := range ks {
+		k.Key = "***"
+		out[i] = k
+	}
+	return out
+}
+
+type Score struct {
+	FetchTimeout Duration `json:"fetch_timeout" env:"FETCH_TIMEOUT" flag:"fetch-timeout" usage:"timeout of one score request, fetch and compute"`
+	Window       int      `json:"window" env:"DEFAULT_WINDOW" flag:"window" usage:"default normalization window in bars"`
+	Tail         int      `json:"tail" env:"DEFAULT_TAIL" flag:"tail" usage:"default number of bars returned without start"`
+	Lang         string   `json:"lang" env:"DEFAULT_LANG" flag:"lang" usage:"default language: zh, zh-TW, en or ja"`
+	Labels       string   `json:"labels" env:"LABEL_SCHEME" flag:"labels" usage:"default label scheme: default, stable, three or one of label_schemes"`
+	Smooth       string   `json:"smooth" env:"SMOOTH" flag:"smooth" usage:"default signal line: none, ema, sma or kalman"`
+	SmoothPeriod int      `json:"smooth_period" env:"SMOOTH_PERIOD" flag:"smooth-period" usage:"default signal line period in bars"`
+	Timeframes   string   `json:"timeframes" env:"TIMEFRAMES" flag:"timeframes" usage:"timeframe weights of the fused score with mtf=true, e.g. 1d:0.5,1wk:0.3,1h:0.2"`
+
+	// Custom label schemes, config file only
+	LabelSchemes []models.LabelScheme `json:"label_schemes"`
+}
+
+type Stream struct {
+	Interval Duration `json:"interval" env:"STREAM_INTERVAL" flag:"stream-interval" usage:"live stream refresh interval"`
+}
+
+type Archive struct {
+	Dir             string   `json:"dir" env:"ARCHIVE_DIR" flag:"archive-dir" usage:"score archive directory"`
+	SnapshotTickers []string `json:"snapshot_tickers" env:"SNAPSHOT_TICKERS" flag:"snapshot-tickers" usage:"comma separated tickers archived after each close, empty disables"`
+	Delay           Duration `json:"delay" env:"SNAPSHOT_DELAY" flag:"snapshot-delay" usage:"wait after the close before archiving"`
+}
+
+type Health struct {
+	ProbeTicker   string   `json:"probe_ticker" env:"HEALTH_PROBE_TICKER" flag:"health-probe-ticker" usage:"ticker fetched to check the provider is reachable, empty disables"`
+	ProbeInterval Duration `json:"probe_interval" env:"HEALTH_PROBE_INTERVAL" flag:"health-probe-interval" usage:"how long a readiness check result is reused"`
+}
+
+type Admin struct {
+	Token string `json:"token" env:"ADMIN_TOKEN" flag:"admin-token" secret:"true" usage:"token for /admin endpoints, empty disables them"`
+}
+
+type Log struct {
+	Format string `json:"format" env:"LOG_FORMAT" flag:"log-format" usage:"json or text"`
+	Level  string `json:"level" env:"LOG_LEVEL" flag:"log-level" usage:"debug, info, warn or error"`
+}
+
+// Default is the configuration without any file, env or flags.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:              8000,
+			GRPCPort:          9090,
+			ReadHeaderTimeout: Duration(5 * time.Second),
+			ReadTimeout:       Duration(15 * time.Second),
+			WriteTimeout:      Duration(15 * time.Second),
+			IdleTimeout:       Duration(60 * time.Second),
+			ShutdownTimeout:   Duration(25 * time.Second),
+		},
+		Data: Data{Provider: "yahoo"},
+		Cache: Cache{
+			Backend:         "memory",
+			Prefix:          "sa:",
+			TTL:             Duration(5 * time.Minute),
+			BarsTTL:         Duration(15 * time.Second),
+			CleanupInterval: Duration(10 * time.Minute),
+		},
+		RateLimit: RateLimit{PerMinute: 60, KeyPerMinute: 600},
+		Score: Score{
+			FetchTimeout: Duration(12 * time.Second),
+			Window:       252,
+			Tail:         600,
+			Lang:         "zh",
+			Labels:       "default",
+			Smooth:       "none",
+			SmoothPeriod: 9,
+			Timeframes:   "1d:0.5,1wk:0.3,1h:0.2",
+		},
+		Stream:  Stream{Interval: Duration(15 * time.Second)},
+		Archive: Archive{Dir: "archive", Delay: Duration(15 * time.Minute)},
+		Log:     Log{Format: "json", Level: "info"},
+		Health:  Health{ProbeTicker: "SPY", ProbeInterval: Duration(30 * time.Second)},
+	}
+}
+
+// LoadFile overlays the file at path onto c, YAML for a .yaml or .yml
+// extension, JSON otherwise. Unknown keys are an error, a typo should not
+// silently fall back to a default.
+func (c *Config) LoadFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if b, err = yamlToJSON(b); err != nil {
+			return fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+	return nil
+}
+
+// yamlToJSON re-encodes a YAML document as JSON, so both go through the same
+// json tags, Duration parsing and unknown key check.
+func yamlToJSON(b []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		// Empty or only comments
+		return []byte("{}"), nil
+	}
+	return json.Marshal(v)
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port < 65536, "server.port %d out of range", c.Server.Port)
+	check(c.Server.GRPCPort >= 0 && c.Server.GRPCPort < 65536, "server.grpc_port %d out of range", c.Server.GRPCPort)
+	check(c.Server.GRPCPort != c.Server.Port, "server.grpc_port must differ from server.port")
+	for _, d := range []struct {
+		name string
+		v    Duration
+	}{
+		{"server.read_header_timeout", c.Server.ReadHeaderTimeout},
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"cache.ttl", c.Cache.TTL},
+		{"cache.bars_ttl", c.Cache.BarsTTL},
+		{"cache.cleanup_interval", c.Cache.CleanupInterval},
+		{"score.fetch_timeout", c.Score.FetchTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+		{"stream.interval", c.Stream.Interval},
+		{"health.probe_interval", c.Health.ProbeInterval},
+	} {
+		check(d.v > 0, "%s must be positive", d.name)
+	}
+	check(c.Server.ShutdownDelay >= 0, "server.shutdown_delay must not be negative")
+	check(c.Archive.Delay >= 0, "archive.delay must not be negative")
+	check(c.Cache.Backend == "memory" || c.Cache.Backend == "redis", "cache.backend %q, expected memory or redis", c.Cache.Backend)
+	check(c.Cache.Backend != "redis" || c.Cache.RedisURL != "", "cache.redis_url required with the redis backend")
+	check(c.Data.Provider == "yahoo" || c.Data.Provider == "fake", "data.provider %q, expected yahoo or fake", c.Data.Provider)
+	check(c.RateLimit.PerMinute >= 0 && c.RateLimit.Burst >= 0 && c.RateLimit.DailyQuota >= 0 &&
+		c.RateLimit.KeyPerMinute >= 0 && c.RateLimit.KeyDailyQuota >= 0, "rate_limit limits must not be negative")
+	check(!c.RateLimit.RequireKey || len(c.RateLimit.Keys) > 0, "rate_limit.require_key without any keys")
+	seen := map[string]bool{}
+	for _, k := range c.RateLimit.Keys {
+		check(k.Name != "" && k.Key != "", "rate_limit.keys: name and key required")
+		check(!seen[k.Name], "rate_limit.keys: duplicate name %q", k.Name)
+		check(k.PerMinute >= 0 && k.Burst >= 0 && k.DailyQuota >= 0, "rate_limit.keys %q: limits must not be negative", k.Name)
+		seen[k.Name] = true
+	}
+	if _, err := ParsePrefixes(c.RateLimit.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("rate_limit.trusted_proxies: %w", err))
+	}
+	check(c.Score.Window > 0, "score.window must be positive")
+	check(c.Score.Tail > 0, "score.tail must be positive")
+	check(i18n.Supported(c.Score.Lang), "score.lang %q, expected one of %s", c.Score.Lang, strings.Join(i18n.Locales(), ", "))
+	schemes := map[string]bool{}
+	for _, s := range c.Score.LabelSchemes {
+		if err := calc.ValidateScheme(s); err != nil {
+			errs = append(errs, fmt.Errorf("score.label_schemes: %w", err))
+		}
+		check(!calc.IsBuiltinScheme(s.ID) && !schemes[s.ID], "score.label_schemes: %q is already taken", s.ID)
+		schemes[s.ID] = true
+	}
+	check(calc.IsBuiltinScheme(c.Score.Labels) || schemes[c.Score.Labels], "score.labels: unknown scheme %q", c.Score.Labels)
+	check(c.Score.SmoothPeriod > 0, "score.smooth_period must be positive")
+	if err := (calc.Smoothing{Method: c.Score.Smooth, Period: max(c.Score.SmoothPeriod, 1)}).Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("score.smooth: %w", err))
+	}
+	if _, err := calc.ParseTimeframes(c.Score.Timeframes); err != nil {
+		errs = append(errs, fmt.Errorf("score.timeframes: %w", err))
+	}
+	check(len(c.Archive.SnapshotTickers) == 0 || c.Archive.Dir != "", "archive.dir required with snapshot_tickers")
+	check(c.Log.Format == "json" || c.Log.Format == "text", "log.format %q, expected json or text", c.Log.Format)
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level %q, expected debug, info, warn or error", c.Log.Level))
+	}
+	return errors.Join(errs...)
+}
+
+// ParsePrefixes parses IPs and CIDRs, a bare IP being a single-address prefix.
+func ParsePrefixes(list []string) ([]netip.Prefix, error) {
+	var out []netip.Prefix
+	for _, s := range list {
+		if strings.Contains(s, "/") {
+			p, err := netip.ParsePrefix(s)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, p.Masked())
+			continue
+		}
+		a, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, netip.PrefixFrom(a, a.BitLen()))
+	}
+	return out, nil
+}
+
+// Duration is a time.Duration written as "15s" in JSON.
+type Duration time.Duration
+
+func (d Duration) D() time.Duration { return time.Duration(d) }
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"15s\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// A YAML file reads like the JSON one with the same keys.
+func TestLoadFileYAML(t *testing.T) {
+	const js = `{
+  "server": { "port": 8080, "write_timeout": "30s" },
+  "score": { "window": 120, "lang": "en", "label_schemes": [
+    { "id": "trend", "hysteresis": 2, "bands": [
+      { "key": "bear", "upper": 50, "names": { "en": "Bearish" } },
+      { "key": "bull" }
+    ] }
+  ] },
+  "archive": { "snapshot_tickers": ["SPY", "QQQ"], "delay": "15m" }
+}`
+	const yml = `# same settings
+server:
+  port: 8080
+  write_timeout: 30s
+score:
+  window: 120
+  lang: en
+  label_schemes:
+    - id: trend
+      hysteresis: 2
+      bands:
+        - {key: bear, upper: 50, names: {en: Bearish}}
+        - key: bull
+archive:
+  snapshot_tickers: [SPY, QQQ]
+  delay: 15m
+`
+	want := Default()
+	if err := want.LoadFile(writeFile(t, "config.json", js)); err != nil {
+		t.Fatal(err)
+	}
+	if want.Server.WriteTimeout.D() != 30*time.Second || want.Archive.Delay.D() != 15*time.Minute {
+		t.Fatalf("durations not read: %+v", want.Server)
+	}
+	for _, name := range []string{"config.yaml", "config.YML"} {
+		got := Default()
+		if err := got.LoadFile(writeFile(t, name, yml)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %+v\nwant %+v", name, got, want)
+		}
+	}
+
+	got := Default()
+	if err := got.LoadFile(writeFile(t, "empty.yaml", "# nothing yet\n")); err != nil || !reflect.DeepEqual(got, Default()) {
+		t.Errorf("empty YAML = %v, want the defaults", err)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"typo.yaml", "server:\n  prot: 8080\n", `unknown field "prot"`},
+		{"typo.json", `{"server": {"prot": 8080}}`, `unknown field "prot"`},
+		{"type.yaml", "server:\n  port: eighty\n", "cannot unmarshal"},
+		{"broken.yaml", "server: [\n", "config "},
+		{"duration.yaml", "cache:\n  ttl: soon\n", "soon"},
+	}
+	for _, tt := range tests {
+		c := Default()
+		err := c.LoadFile(writeFile(t, tt.name, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Load builds the effective configuration from defaults, the file named by
+// -config or CONFIG_FILE, the environment and args, then validates it.
+// getenv is usually os.Getenv.
+func Load(name string, args []string, getenv func(string) string) (Config, error) {
+	cfg, rest, err := Parse(flag.NewFlagSet(name, flag.ContinueOnError), args, getenv)
+	if err == nil && len(rest) > 0 {
+		err = fmt.Errorf("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	return cfg, err
+}
+
+// Parse is Load for a flag set the caller may have added its own flags to, a
+// CLI subcommand for example. Flags and positional arguments can be mixed,
+// the positional ones are returned.
+func Parse(fs *flag.FlagSet, args []string, getenv func(string) string) (Config, []string, error) {
+	cfg := Default()
+
+	path := fs.String("config", getenv("CONFIG_FILE"), "JSON or YAML config file")
+	set := map[string]string{}
+	walk(&cfg, func(f field) {
+		if f.flag != "" {
+			fs.Func(f.flag, f.usage+" (env "+f.env+")", func(s string) error {
+				// Checked after the file and env are applied, flags win
+				set[f.flag] = s
+				return nil
+			})
+		}
+	})
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return cfg, nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		// flag stops at the first positional argument, pick it and go on
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if *path != "" {
+		if err := cfg.LoadFile(*path); err != nil {
+			return cfg, rest, err
+		}
+	}
+
+	var err error
+	walk(&cfg, func(f field) {
+		if err != nil {
+			return
+		}
+		if s := getenv(f.env); f.env != "" && s != "" {
+			if e := f.set(s); e != nil {
+				err = fmt.Errorf("env %s: %w", f.env, e)
+			}
+		}
+	})
+	if err != nil {
+		return cfg, rest, err
+	}
+	walk(&cfg, func(f field) {
+		if err != nil {
+			return
+		}
+		if s, ok := set[f.flag]; ok {
+			if e := f.set(s); e != nil {
+				err = fmt.Errorf("flag -%s: %w", f.flag, e)
+			}
+		}
+	})
+	if err != nil {
+		return cfg, rest, err
+	}
+
+	return cfg, rest, cfg.Validate()
+}
+
+// field is one leaf setting of Config.
+type field struct {
+	env, flag, usage string
+	secret           bool
+	v                reflect.Value
+}
+
+var durationType = reflect.TypeOf(Duration(0))
+
+func (f field) set(s string) error {
+	if v, ok := f.v.Addr().Interface().(interface{ Set(string) error }); ok {
+		return v.Set(s)
+	}
+	switch {
+	case f.v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		f.v.SetInt(int64(d))
+	case f.v.Kind() == reflect.String:
+		f.v.SetString(s)
+	case f.v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", s)
+		}
+		f.v.SetInt(int64(n))
+	case f.v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.v.SetBool(b)
+	case f.v.Kind() == reflect.Slice && f.v.Type().Elem().Kind() == reflect.String:
+		var list []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		f.v.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.v.Type())
+	}
+	return nil
+}
+
+// walk calls fn for every leaf field of cfg, in declaration order.
+func walk(cfg *Config, fn func(field)) {
+	var visit func(v reflect.Value)
+	visit = func(v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf, fv := t.Field(i), v.Field(i)
+			if fv.Kind() == reflect.Struct {
+				visit(fv)
+				continue
+			}
+			fn(field{
+				env:    sf.Tag.Get("env"),
+				flag:   sf.Tag.Get("flag"),
+				usage:  sf.Tag.Get("usage"),
+				secret: sf.Tag.Get("secret") == "true",
+				v:      fv,
+			})
+		}
+	}
+	visit(reflect.ValueOf(cfg).Elem())
+}
+
+// masker is a secret setting that hides its own secret parts, e.g. the keys
+// but not the names of APIKeys.
+type masker interface {
+	mask() interface{}
+}
+
+// Public is a copy safe to show: secret settings that are set read "***".
+func (c Config) Public() Config {
+	walk(&c, func(f field) {
+		if !f.secret || f.v.IsZero() {
+			return
+		}
+		if m, ok := f.v.Interface().(masker); ok {
+			f.v.Set(reflect.ValueOf(m.mask()))
+			return
+		}
+		switch f.v.Kind() {
+		case reflect.String:
+			f.v.SetString("***")
+		case reflect.Slice:
+			masked := make([]string, f.v.Len())
+			for i := range masked {
+				masked[i] = "***"
+			}
+			f.v.Set(reflect.ValueOf(masked))
+		}
+	})
+	return c
+}
@@ -0,0 +1,126 @@
+// Package fake is a deterministic synthetic bar source, for running the server
+// and the live stream without hitting Yahoo.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Provider generates a random walk per ticker. Completed bars never change, the
+// bar in progress moves every Tick, so a stream sees fresh prices without any
+// real market being open.
+type Provider struct {
+	// Tick is how often the bar in progress changes. Zero means every second.
+	Tick time.Duration
+	// Now is the clock, time.Now if nil.
+	Now func() time.Time
+}
+
+// epoch is where every synthetic series starts.
+var epoch = time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC)
+
+func (p *Provider) GetPrices(ctx context.Context, ticker string, start, end time.Time, freq string) (*models.PriceFrame, error) {
+	step, err := barStep(freq)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if p.Now != nil {
+		now = p.Now()
+	}
+	if end.IsZero() || end.After(now) {
+		end = now
+	}
+	if start.IsZero() || start.Before(epoch) {
+		start = end.AddDate(-2, 0, 0)
+		if start.Before(epoch) {
+			start = epoch
+		}
+	}
+
+	ticker = strings.ToUpper(ticker)
+	first := int(start.Sub(epoch) / step)
+	last := int(end.Sub(epoch) / step)
+	live := int(now.Sub(epoch) / step)
+	pf := &models.PriceFrame{Ticker: ticker, Frequency: freq}
+	if last < first {
+		return pf, nil
+	}
+
+	// The walk is replayed from the epoch so a bar is the same whatever the range
+	closePx := 100.0
+	seed := tickerSeed(ticker)
+	for i := 0; i <= last; i++ {
+		if i%4096 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		key := seed + uint64(i)
+		if i == live {
+			// Bar in progress: re-roll the move every tick
+			tick := p.Tick
+			if tick <= 0 {
+				tick = time.Second
+			}
+			key = seed ^ uint64(now.UnixNano()/int64(tick))
+		}
+		open := closePx
+		closePx = open * math.Exp(normal(key, 0)*0.015)
+		if i < first {
+			continue
+		}
+		spread := math.Abs(normal(key, 1)) * 0.005
+		pf.Prices = append(pf.Prices, models.Price{
+			Date:   epoch.Add(time.Duration(i) * step),
+			Open:   open,
+			High:   math.Max(open, closePx) * (1 + spread),
+			Low:    math.Min(open, closePx) * (1 - spread),
+			Close:  closePx,
+			Volume: 1e6 * (1 + uniform(key, 2)),
+		})
+	}
+	return pf, nil
+}
+
+func barStep(freq string) (time.Duration, error) {
+	switch freq {
+	case "1d":
+		return 24 * time.Hour, nil
+	case "1h":
+		return time.Hour, nil
+	}
+	return 0, fmt.Errorf("fake: unsupported frequency %q", freq)
+}
+
+func tickerSeed(ticker string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(ticker))
+	return h.Sum64()
+}
+
+// uniform is a stateless PRNG (splitmix64) in [0, 1): draw n of bar key.
+func uniform(key, n uint64) float64 {
+	z := key + (n+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+	return float64(z>>11) / (1 << 53)
+}
+
+// normal is a standard normal draw via Box-Muller.
+func normal(key, n uint64) float64 {
+	u1 := uniform(key, 2*n+10)
+	u2 := uniform(key, 2*n+11)
+	if u1 == 0 {
+		u1 = math.SmallestNonzeroFloat64
+	}
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
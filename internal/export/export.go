@@ -0,0 +1,122 @@
+package export
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Supported formats and their media types
+const (
+	CSV    = "csv"
+	XLSX   = "xlsx"
+	NDJSON = "ndjson"
+)
+
+var ContentTypes = map[string]string{
+	CSV:    "text/csv; charset=utf-8",
+	XLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	NDJSON: "application/x-ndjson",
+}
+
+// Row is one bar of one ticker. Date is already formatted for the exchange.
+type Row struct {
+	Ticker string
+	Date   string
+	Result models.ScoreResult
+}
+
+// Writer streams rows in one of the export formats. Close must be called to
+// finish the document.
+type Writer interface {
+	WriteRow(Row) error
+	// WriteError records a ticker that could not be scored. Tabular formats skip it.
+	WriteError(ticker string, e models.ErrorBody) error
+	// Flush pushes buffered rows to the underlying writer
+	Flush() error
+	Close() error
+}
+
+// column is one exported field. Numeric columns return NaN for missing values.
+type column struct {
+	Key     string
+	numeric bool
+	value   func(Row) float64
+	text    func(Row) string
+}
+
+var components = []struct {
+	id    string
+	score func(models.ScoreResult) float64
+	raw   func(models.ScoreResult) float64
+}{
+	{"trend", func(r models.ScoreResult) float64 { return r.Values.Trend }, func(r models.ScoreResult) float64 { return r.Raw.Trend }},
+	{"momentum", func(r models.ScoreResult) float64 { return r.Values.Momentum }, func(r models.ScoreResult) float64 { return r.Raw.Momentum }},
+	{"rsi", func(r models.ScoreResult) float64 { return r.Values.RSI }, func(r models.ScoreResult) float64 { return r.Raw.RSI }},
+	{"macd", func(r models.ScoreResult) float64 { return r.Values.MACD }, func(r models.ScoreResult) float64 { return r.Raw.MACD }},
+	{"drawdown", func(r models.ScoreResult) float64 { return r.Values.Drawdown }, func(r models.ScoreResult) float64 { return r.Raw.Drawdown }},
+	{"volatility", func(r models.ScoreResult) float64 { return r.Values.Vol }, func(r models.ScoreResult) float64 { return r.Raw.Vol }},
+	{"mfi", func(r models.ScoreResult) float64 { return r.Values.MFI }, func(r models.ScoreResult) float64 { return r.Raw.MFI }},
+	{"bb_pct_b", func(r models.ScoreResult) float64 { return r.Values.BB }, func(r models.ScoreResult) float64 { return r.Raw.BB }},
+}
+
+func columns() []column {
+	cols := []column{
+		{Key: "ticker", text: func(r Row) string { return r.Ticker }},
+		{Key: "date", text: func(r Row) string { return r.Date }},
+		{Key: "price", numeric: true, value: func(r Row) float64 { return r.Result.Price }},
+		{Key: "score", numeric: true, value: func(r Row) float64 { return r.Result.Score }},
+		{Key: "label", text: func(r Row) string { return r.Result.Label }},
+	}
+	for _, c := range components {
+		score := c.score
+		cols = append(cols, column{Key: c.id, numeric: true, value: func(r Row) float64 { return score(r.Result) }})
+	}
+	for _, c := range components {
+		raw := c.raw
+		cols = append(cols, column{Key: c.id + "_raw", numeric: true, value: func(r Row) float64 { return raw(r.Result) }})
+	}
+	return cols
+}
+
+// headers returns the localized column titles, the default locale for an
+// unknown lang like the API.
+func headers(cols []column, lang string) []string {
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		id, raw := strings.CutSuffix(c.Key, "_raw")
+		switch {
+		case raw:
+			out[i] = i18n.T(lang, "component."+id+".name") + " " + i18n.T(lang, "export.raw")
+		case isComponent(id):
+			out[i] = i18n.T(lang, "component."+id+".name")
+		default:
+			out[i] = i18n.T(lang, "export."+id)
+		}
+	}
+	return out
+}
+
+func isComponent(id string) bool {
+	for _, c := range components {
+		if c.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// cell renders a column as text; missing numbers become "".
+func (c column) cell(r Row) string {
+	if !c.numeric {
+		return c.text(r)
+	}
+	v := c.value(r)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,205 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// New returns a Writer for format, writing localized headers for lang.
+func New(format string, w io.Writer, lang string) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSV(w, lang)
+	case XLSX:
+		return newXLSX(w, lang)
+	case NDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w), cols: columns()}, nil
+	}
+	return nil, fmt.Errorf("export: unknown format %q", format)
+}
+
+// CSV
+
+type csvWriter struct {
+	w    *csv.Writer
+	cols []column
+}
+
+func newCSV(w io.Writer, lang string) (*csvWriter, error) {
+	// BOM so Excel opens UTF-8 (Chinese headers) correctly
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{w: csv.NewWriter(w), cols: columns()}
+	if err := cw.w.Write(headers(cw.cols, lang)); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (c *csvWriter) WriteRow(r Row) error {
+	rec := make([]string, len(c.cols))
+	for i, col := range c.cols {
+		rec[i] = col.cell(r)
+	}
+	return c.w.Write(rec)
+}
+
+func (c *csvWriter) WriteError(string, models.ErrorBody) error { return nil }
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// NDJSON: machine keys, missing numbers are null
+
+type ndjsonWriter struct {
+	enc  *json.Encoder
+	cols []column
+}
+
+func (n *ndjsonWriter) WriteRow(r Row) error {
+	obj := make(map[string]interface{}, len(n.cols))
+	for _, col := range n.cols {
+		if !col.numeric {
+			obj[col.Key] = col.text(r)
+			continue
+		}
+		v := col.value(r)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			obj[col.Key] = nil
+		} else {
+			obj[col.Key] = v
+		}
+	}
+	return n.enc.Encode(obj)
+}
+
+func (n *ndjsonWriter) WriteError(ticker string, e models.ErrorBody) error {
+	return n.enc.Encode(map[string]interface{}{"ticker": ticker, "error": e})
+}
+
+func (n *ndjsonWriter) Flush() error { return nil }
+
+func (n *ndjsonWriter) Close() error { return nil }
+
+// XLSX: the smallest valid workbook, one sheet with inline strings. The sheet
+// is written straight into the zip stream, so rows never pile up in memory.
+
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	cols  []column
+	row   int
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+const xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Fear &amp; Greed" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+func newXLSX(w io.Writer, lang string) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, f := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return nil, err
+		}
+	}
+
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	x := &xlsxWriter{zw: zw, sheet: bufio.NewWriter(fw), cols: columns()}
+	x.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	x.sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	titles := headers(x.cols, lang)
+	x.startRow()
+	for _, t := range titles {
+		x.stringCell(t)
+	}
+	x.sheet.WriteString("</row>")
+	return x, nil
+}
+
+func (x *xlsxWriter) startRow() {
+	x.row++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.row)
+}
+
+func (x *xlsxWriter) stringCell(s string) {
+	x.sheet.WriteString(`<c t="inlineStr"><is><t>`)
+	xmlEscape(x.sheet, s)
+	x.sheet.WriteString(`</t></is></c>`)
+}
+
+func (x *xlsxWriter) WriteRow(r Row) error {
+	x.startRow()
+	for _, col := range x.cols {
+		if !col.numeric {
+			x.stringCell(col.text(r))
+			continue
+		}
+		if s := col.cell(r); s != "" {
+			x.sheet.WriteString("<c><v>" + s + "</v></c>")
+		} else {
+			x.sheet.WriteString("<c/>")
+		}
+	}
+	_, err := x.sheet.WriteString("</row>")
+	return err
+}
+
+func (x *xlsxWriter) WriteError(string, models.ErrorBody) error { return nil }
+
+func (x *xlsxWriter) Flush() error {
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Flush()
+}
+
+func (x *xlsxWriter) Close() error {
+	x.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+var xmlReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func xmlEscape(w *bufio.Writer, s string) {
+	xmlReplacer.WriteString(w, s)
+}
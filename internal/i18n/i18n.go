@@ -0,0 +1,281 @@
+// Package i18n is the message catalog: labels, component texts, errors and
+// the web UI strings, one embedded JSON file per locale.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the locale used when a request asks for none or an unknown one.
+const Default = "zh"
+
+//go:embed locales/*.json
+var files embed.FS
+
+// locale is one file. A message missing here is looked up in Fallback, so a
+// regional variant only needs the messages that differ.
+type locale struct {
+	Fallback string         `json:"fallback"`
+	Messages map[string]any `json:"messages"` // nested objects, keys joined with "."
+
+	name  string
+	flat  map[string]string
+	chain []*locale // itself and its fallbacks, in lookup order
+}
+
+var catalog = map[string]*locale{}
+
+// keys is every message key of every locale, sorted.
+var keys []string
+
+func init() {
+	if err := load(); err != nil {
+		// The catalog is embedded, a broken one is a build mistake
+		panic("i18n: " + err.Error())
+	}
+}
+
+func load() error {
+	names, err := files.ReadDir("locales")
+	if err != nil {
+		return err
+	}
+	all := map[string]bool{}
+	for _, n := range names {
+		b, err := files.ReadFile(path.Join("locales", n.Name()))
+		if err != nil {
+			return err
+		}
+		l := &locale{flat: map[string]string{}}
+		if err := json.Unmarshal(b, l); err != nil {
+			return fmt.Errorf("%s: %w", n.Name(), err)
+		}
+		if err := flatten(l.flat, "", l.Messages); err != nil {
+			return fmt.Errorf("%s: %w", n.Name(), err)
+		}
+		for k := range l.flat {
+			all[k] = true
+		}
+		l.name = strings.TrimSuffix(n.Name(), ".json")
+		catalog[l.name] = l
+	}
+	if _, ok := catalog[Default]; !ok {
+		return fmt.Errorf("no %s locale", Default)
+	}
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Every locale must say everything, itself or through its fallbacks
+	for name := range catalog {
+		chain, err := fallbacks(name)
+		if err != nil {
+			return err
+		}
+		catalog[name].chain = chain
+		for _, k := range keys {
+			if _, ok := lookup(chain, k); !ok {
+				return fmt.Errorf("%s: missing %q", name, k)
+			}
+		}
+	}
+	return nil
+}
+
+func flatten(dst map[string]string, prefix string, m map[string]any) error {
+	for k, v := range m {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case string:
+			dst[k] = v
+		case map[string]any:
+			if err := flatten(dst, k, v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: expected a string or an object", k)
+		}
+	}
+	return nil
+}
+
+// fallbacks is the lookup order of a locale, itself first.
+func fallbacks(name string) ([]*locale, error) {
+	var chain []*locale
+	seen := map[string]bool{}
+	for name != "" {
+		l, ok := catalog[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown fallback locale %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("fallback loop at %q", name)
+		}
+		seen[name] = true
+		chain = append(chain, l)
+		name = l.Fallback
+	}
+	return chain, nil
+}
+
+func lookup(chain []*locale, key string) (string, bool) {
+	for _, l := range chain {
+		if s, ok := l.flat[key]; ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Chain lists the locale of lang and the ones it falls back to, in lookup
+// order, ending with Default.
+func Chain(lang string) []string {
+	var out []string
+	for _, l := range catalog[resolve(lang)].chain {
+		out = append(out, l.name)
+	}
+	if out[len(out)-1] != Default {
+		out = append(out, Default)
+	}
+	return out
+}
+
+// Locales lists the supported locales, sorted.
+func Locales() []string {
+	out := make([]string, 0, len(catalog))
+	for name := range catalog {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Supported reports whether lang is a locale as spelled by Locales.
+func Supported(lang string) bool {
+	_, ok := catalog[lang]
+	return ok
+}
+
+// scripts maps the Chinese script and region subtags to a locale.
+var scripts = map[string]string{
+	"hans": "zh", "cn": "zh", "sg": "zh",
+	"hant": "zh-TW", "tw": "zh-TW", "hk": "zh-TW", "mo": "zh-TW",
+}
+
+// Match finds the locale of a language tag such as "zh-Hant-HK", "ja_JP" or
+// "EN", false when there is none.
+func Match(tag string) (string, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if tag == "" {
+		return "", false
+	}
+	for name := range catalog {
+		if strings.ToLower(name) == tag {
+			return name, true
+		}
+	}
+	parts := strings.Split(tag, "-")
+	if parts[0] == "zh" {
+		for _, p := range parts[1:] {
+			if name, ok := scripts[p]; ok && Supported(name) {
+				return name, true
+			}
+		}
+	}
+	if Supported(parts[0]) {
+		return parts[0], true
+	}
+	return "", false
+}
+
+// Negotiate picks the locale of an Accept-Language header, by quality and
+// then order. False when none of the languages is supported.
+func Negotiate(header string) (string, bool) {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if name, ok := Match(tag); ok && q > bestQ {
+			best, bestQ = name, q
+		}
+	}
+	return best, best != ""
+}
+
+// resolve is the locale of lang, Default when there is none.
+func resolve(lang string) string {
+	if Supported(lang) {
+		return lang
+	}
+	if name, ok := Match(lang); ok {
+		return name
+	}
+	return Default
+}
+
+// T returns the message key in lang, formatted with args like fmt.Sprintf.
+// Unknown locales use Default, unknown keys come back as the key itself.
+func T(lang, key string, args ...any) string {
+	msg, ok := message(lang, key)
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+func message(lang, key string) (string, bool) {
+	if s, ok := lookup(catalog[resolve(lang)].chain, key); ok {
+		return s, true
+	}
+	return lookup(catalog[Default].chain, key)
+}
+
+// Tree returns the messages under prefix as nested maps, e.g. for the
+// templates. Keys missing in lang come from its fallbacks. Only the prefix is
+// split on ".", keys below it may contain dots ("0700.HK").
+func Tree(lang, prefix string) map[string]any {
+	chain := catalog[resolve(lang)].chain
+	out := map[string]any{}
+	// Deepest fallback first, so lang wins
+	for i := len(chain) - 1; i >= 0; i-- {
+		m := chain[i].Messages
+		for _, p := range strings.Split(prefix, ".") {
+			m, _ = m[p].(map[string]any)
+		}
+		merge(out, m)
+	}
+	return out
+}
+
+func merge(dst, src map[string]any) {
+	for k, v := range src {
+		if sub, ok := v.(map[string]any); ok {
+			d, ok := dst[k].(map[string]any)
+			if !ok {
+				d = map[string]any{}
+				dst[k] = d
+			}
+			merge(d, sub)
+			continue
+		}
+		dst[k] = v
+	}
+}
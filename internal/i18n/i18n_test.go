@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"slices"
+	"testing"
+)
+
+// inherited lists the messages a locale knowingly leaves to its fallbacks,
+// because they read the same there. Any other key it lacks is a translation
+// somebody forgot, e.g. a key added to en and zh only.
+var inherited = map[string][]string{
+	"zh-TW": {
+		"component.macd.name",
+		"component.rsi.name",
+		"error.api_key_missing",
+		"error.purge_target",
+		"export.date",
+		"export.raw",
+		"label.neutral",
+		"ui.chartMonth",
+		"ui.date",
+		"ui.indices.SOL-USD",
+	},
+	"ja": {
+		"component.macd.name",
+		"component.rsi.name",
+	},
+}
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		lang string
+		want []string
+	}{
+		{"zh", []string{"zh"}},
+		{"zh-TW", []string{"zh-TW", "zh"}},
+		{"zh-Hant-HK", []string{"zh-TW", "zh"}},
+		{"en", []string{"en", "zh"}},
+		{"ja", []string{"ja", "en", "zh"}},
+		{"ja_JP", []string{"ja", "en", "zh"}},
+		{"", []string{"zh"}},
+		{"xx", []string{"zh"}},
+	}
+	for _, tt := range tests {
+		if got := Chain(tt.lang); !slices.Equal(got, tt.want) {
+			t.Errorf("Chain(%q) = %v, want %v", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestEveryKeyResolves(t *testing.T) {
+	for _, lang := range Locales() {
+		for _, k := range keys {
+			from := ""
+			for _, name := range Chain(lang) {
+				if _, ok := catalog[name].flat[k]; ok {
+					from = name
+					break
+				}
+			}
+			switch {
+			case from == "":
+				t.Errorf("%s: %q resolves nowhere along %v", lang, k, Chain(lang))
+			case from != lang && !slices.Contains(inherited[lang], k):
+				t.Errorf("%s: %q comes from %s, translate it or add it to inherited", lang, k, from)
+			case T(lang, k) != catalog[from].flat[k]:
+				t.Errorf("%s: T(%q) = %q, want %q from %s", lang, k, T(lang, k), catalog[from].flat[k], from)
+			}
+		}
+	}
+}
+
+// An entry of inherited that got translated meanwhile, or whose key is gone,
+// is stale.
+func TestInheritedIsCurrent(t *testing.T) {
+	for lang, ks := range inherited {
+		l, ok := catalog[lang]
+		if !ok {
+			t.Errorf("inherited lists unknown locale %s", lang)
+			continue
+		}
+		if l.Fallback == "" {
+			t.Errorf("%s has no fallback to inherit from", lang)
+		}
+		for _, k := range ks {
+			if _, ok := l.flat[k]; ok {
+				t.Errorf("%s: %q is translated, drop it from inherited", lang, k)
+			}
+			if _, ok := slices.BinarySearch(keys, k); !ok {
+				t.Errorf("%s: %q is no message anymore, drop it from inherited", lang, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"context"
+	"strings"
+)
+
+// TraceContext is the W3C trace context of a request as seen by this server:
+// the caller's trace ID with our own span ID.
+type TraceContext struct {
+	TraceID  string // 32 hex
+	SpanID   string // 16 hex, this server's span
+	ParentID string // 16 hex, caller's span, "" if we started the trace
+	Flags    string // 2 hex
+}
+
+// Traceparent is the header value to send downstream, with this server's span
+// as the parent.
+func (tc TraceContext) Traceparent() string {
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + tc.Flags
+}
+
+// ParseTraceparent parses a version 00 traceparent header. Invalid headers
+// are ignored, as the spec asks.
+func ParseTraceparent(h string) (traceID, parentID, flags string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(h), "-")
+	if len(parts) < 4 || parts[0] == "ff" || len(parts[0]) != 2 || !isHex(parts[0]) {
+		return "", "", "", false
+	}
+	// Future versions may append fields, version 00 may not
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", "", "", false
+	}
+	traceID, parentID, flags = parts[1], parts[2], parts[3]
+	if len(traceID) != 32 || !isHex(traceID) || traceID == strings.Repeat("0", 32) ||
+		len(parentID) != 16 || !isHex(parentID) || parentID == strings.Repeat("0", 16) ||
+		len(flags) != 2 || !isHex(flags) {
+		return "", "", "", false
+	}
+	return traceID, parentID, flags, true
+}
+
+// StartSpan continues the trace of an incoming traceparent header, or starts a
+// new unsampled trace when it is missing or invalid.
+func StartSpan(traceparent string) TraceContext {
+	if traceID, parentID, flags, ok := ParseTraceparent(traceparent); ok {
+		return TraceContext{TraceID: traceID, SpanID: NewID(8), ParentID: parentID, Flags: flags}
+	}
+	return TraceContext{TraceID: NewID(16), SpanID: NewID(8), Flags: "00"}
+}
+
+// WithTrace returns a context carrying tc.
+func WithTrace(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceKey, tc)
+}
+
+// Trace is the trace context in ctx.
+func Trace(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceKey).(TraceContext)
+	return tc, ok
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
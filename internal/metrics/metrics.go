@@ -0,0 +1,258 @@
+// Package metrics is a small Prometheus client: counters, histograms and
+// gauge callbacks, exposed in the text exposition format. Enough for /metrics
+// without pulling in client_golang.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are latency buckets in seconds, from a cache hit to a slow Yahoo.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+var (
+	mu         sync.Mutex
+	collectors = map[string]collector{}
+)
+
+func register(name string, c collector) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := collectors[name]; dup {
+		panic("metrics: duplicate metric " + name)
+	}
+	collectors[name] = c
+}
+
+// CounterVec is a counter per combination of label values.
+type CounterVec struct {
+	name, help string
+	labels     []string
+
+	mu     sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labels []string
+	v      float64
+}
+
+// NewCounterVec registers a counter. Label values are passed to Inc and Add
+// in the same order as labels.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels, values: map[string]*counterValue{}}
+	if len(labels) == 0 {
+		// A plain counter is exposed as 0 before the first Inc
+		c.values[""] = &counterValue{}
+	}
+	register(name, c)
+	return c
+}
+
+func (c *CounterVec) Inc(lvs ...string) { c.Add(1, lvs...) }
+
+func (c *CounterVec) Add(v float64, lvs ...string) {
+	checkLabels(c.name, c.labels, lvs)
+	k := strings.Join(lvs, "\xff")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cv, ok := c.values[k]
+	if !ok {
+		cv = &counterValue{labels: append([]string(nil), lvs...)}
+		c.values[k] = cv
+	}
+	cv.v += v
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header(w, c.name, c.help, "counter")
+	for _, k := range sortedKeys(c.values) {
+		cv := c.values[k]
+		sample(w, c.name, c.labels, cv.labels, "", "", cv.v)
+	}
+}
+
+// HistogramVec is a histogram per combination of label values.
+type HistogramVec struct {
+	name, help string
+	labels     []string
+	buckets    []float64
+
+	mu     sync.Mutex
+	values map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labels []string
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec registers a histogram with the given upper bounds, sorted
+// ascending. +Inf is implicit.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{name: name, help: help, labels: labels, buckets: buckets, values: map[string]*histogramValue{}}
+	register(name, h)
+	return h
+}
+
+func (h *HistogramVec) Observe(v float64, lvs ...string) {
+	checkLabels(h.name, h.labels, lvs)
+	k := strings.Join(lvs, "\xff")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hv, ok := h.values[k]
+	if !ok {
+		hv = &histogramValue{labels: append([]string(nil), lvs...), counts: make([]uint64, len(h.buckets))}
+		h.values[k] = hv
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		hv.counts[i]++
+	}
+	hv.count++
+	hv.sum += v
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	header(w, h.name, h.help, "histogram")
+	for _, k := range sortedKeys(h.values) {
+		hv := h.values[k]
+		var cum uint64
+		for i, le := range h.buckets {
+			cum += hv.counts[i]
+			sample(w, h.name+"_bucket", h.labels, hv.labels, "le", formatFloat(le), float64(cum))
+		}
+		sample(w, h.name+"_bucket", h.labels, hv.labels, "le", "+Inf", float64(hv.count))
+		sample(w, h.name+"_sum", h.labels, hv.labels, "", "", hv.sum)
+		sample(w, h.name+"_count", h.labels, hv.labels, "", "", float64(hv.count))
+	}
+}
+
+type gaugeFunc struct {
+	name, help string
+	f          func() float64
+}
+
+// NewGaugeFunc registers a gauge read from f at scrape time.
+func NewGaugeFunc(name, help string, f func() float64) {
+	register(name, &gaugeFunc{name: name, help: help, f: f})
+}
+
+func (g *gaugeFunc) write(w *bufio.Writer) {
+	header(w, g.name, g.help, "gauge")
+	sample(w, g.name, nil, nil, "", "", g.f())
+}
+
+// WriteText writes every registered metric in the Prometheus text format.
+func WriteText(out io.Writer) error {
+	mu.Lock()
+	names := make([]string, 0, len(collectors))
+	for n := range collectors {
+		names = append(names, n)
+	}
+	cs := make([]collector, 0, len(names))
+	sort.Strings(names)
+	for _, n := range names {
+		cs = append(cs, collectors[n])
+	}
+	mu.Unlock()
+
+	w := bufio.NewWriter(out)
+	for _, c := range cs {
+		c.write(w)
+	}
+	return w.Flush()
+}
+
+// Handler serves WriteText.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = WriteText(w)
+	})
+}
+
+// FreqLabel bounds a user supplied bar frequency to the known ones, so odd
+// query strings can't create new series.
+func FreqLabel(freq string) string {
+	switch freq {
+	case "1m", "5m", "15m", "30m", "1h", "1d", "1wk", "1mo":
+		return freq
+	}
+	return "other"
+}
+
+func checkLabels(name string, labels, lvs []string) {
+	if len(labels) != len(lvs) {
+		panic(fmt.Sprintf("metrics: %s wants %d label values, got %d", name, len(labels), len(lvs)))
+	}
+}
+
+func header(w *bufio.Writer, name, help, typ string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func sample(w *bufio.Writer, name string, labels, lvs []string, extraName, extraValue string, v float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, l, labelEscaper.Replace(lvs[i]))
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, extraName, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+var (
+	providerFetchSeconds = NewHistogramVec("provider_fetch_duration_seconds",
+		"Time spent fetching bars from the data provider.", DefBuckets, "provider", "freq")
+	providerErrors = NewCounterVec("provider_fetch_errors_total",
+		"Failed provider fetches by error class.", "provider", "class")
+	providerBars = NewCounterVec("provider_bars_total",
+		"Bars returned by the data provider.", "provider")
+)
+
+// InstrumentProvider records latency, errors and bar counts of p under name.
+// If p keeps bar revisions the result does too.
+func InstrumentProvider(p models.Provider, name string) models.Provider {
+	ip := &instrumented{Provider: p, name: name}
+	if ap, ok := p.(models.AsOfProvider); ok {
+		return &instrumentedAsOf{instrumented: ip, asOf: ap}
+	}
+	return ip
+}
+
+type instrumented struct {
+	models.Provider
+	name string
+}
+
+func (p *instrumented) GetPrices(ctx context.Context, ticker string, start, end time.Time, freq string) (*models.PriceFrame, error) {
+	t0 := time.Now()
+	pf, err := p.Provider.GetPrices(ctx, ticker, start, end, freq)
+	bars := 0
+	if err == nil {
+		bars = len(pf.Prices)
+		providerBars.Add(float64(bars), p.name)
+	}
+	p.observe(ctx, t0, ticker, freq, bars, err)
+	return pf, err
+}
+
+func (p *instrumented) observe(ctx context.Context, t0 time.Time, ticker, freq string, bars int, err error) {
+	elapsed := time.Since(t0)
+	providerFetchSeconds.Observe(elapsed.Seconds(), p.name, FreqLabel(freq))
+	if err != nil {
+		providerErrors.Inc(p.name, ErrorClass(err))
+	}
+	slog.DebugContext(ctx, "provider fetch", "provider", p.name, "ticker", ticker, "freq", freq,
+		"bars", bars, "duration_ms", float64(elapsed.Microseconds())/1000, "err", err)
+}
+
+type instrumentedAsOf struct {
+	*instrumented
+	asOf models.AsOfProvider
+}
+
+func (p *instrumentedAsOf) GetHistory(ctx context.Context, ticker string, start, end time.Time, freq string) (models.BarHistory, error) {
+	t0 := time.Now()
+	h, err := p.asOf.GetHistory(ctx, ticker, start, end, freq)
+	p.observe(ctx, t0, ticker, freq, 0, err)
+	return h, err
+}
+
+// ErrorClass buckets an error for the errors_total label: timeout, canceled,
+// network or other.
+func ErrorClass(err error) string {
+	var ne net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &ne) && ne.Timeout():
+		return "timeout"
+	case errors.As(err, &ne):
+		return "network"
+	}
+	return "other"
+}
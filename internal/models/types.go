@@ -1,6 +1,28 @@
 package models
 
-import "time"
+import (
+	"context"
+	"time"
+)
+
+// Provider fetches OHLCV bars for a ticker. end may be zero for "up to now".
+type Provider interface {
+	GetPrices(ctx context.Context, ticker string, start, end time.Time, freq string) (*PriceFrame, error)
+}
+
+// BarHistory resolves bars as they were known at a point in time.
+type BarHistory interface {
+	// AsOf returns the bars dated at or before knownAt, each in the latest
+	// revision recorded at or before knownAt.
+	AsOf(knownAt time.Time) *PriceFrame
+}
+
+// AsOfProvider is implemented by bar stores that keep every revision of a bar,
+// so point-in-time queries can ignore corrections recorded later.
+type AsOfProvider interface {
+	Provider
+	GetHistory(ctx context.Context, ticker string, start, end time.Time, freq string) (BarHistory, error)
+}
 
 // Price represents a single candle
 type Price struct {
@@ -19,12 +41,30 @@ type PriceFrame struct {
 	Prices    []Price
 }
 
+// Truncate returns a copy of the frame holding only bars dated at or before t.
+// Prices are assumed sorted by date.
+func (pf *PriceFrame) Truncate(t time.Time) *PriceFrame {
+	n := len(pf.Prices)
+	for n > 0 && pf.Prices[n-1].Date.After(t) {
+		n--
+	}
+	return &PriceFrame{
+		Ticker:    pf.Ticker,
+		Frequency: pf.Frequency,
+		Prices:    pf.Prices[:n:n],
+	}
+}
+
 // ScoreResult represents the fear & greed score for a single day
 type ScoreResult struct {
 	Date   time.Time `json:"date"`
 	Score  float64   `json:"score"`
 	Label  string    `json:"label"`
-	Price  float64   `json:"price"` // Added Price
+	Band   string    `json:"band"`   // key of the label band, "" for NaN
+	Signal float64   `json:"signal"` // smoothed score, NaN without smoothing
+	Cross  string    `json:"cross"`  // crossover of score and signal on this bar
+	Regime string    `json:"regime"` // market regime of the bar, "" before it can be told
+	Price  float64   `json:"price"`  // Added Price
 	Values struct {
 		Trend    float64 `json:"trend"`
 		Momentum float64 `json:"momentum"`
@@ -47,6 +87,35 @@ type ScoreResult struct {
 		MFI      float64 `json:"mfi_raw"`      // New
 		BB       float64 `json:"bb_pct_b_raw"` // New
 	} `json:"raw"`
+
+	// With several timeframes: the score of each as of this bar and their blend
+	Fused      float64            `json:"fused"`
+	Timeframes map[string]float64 `json:"timeframes,omitempty"`
+
+	Quality DataQuality `json:"quality"`
+}
+
+// DataQuality tells how much data a score rests on. All zero without a score.
+type DataQuality struct {
+	Window     int     `json:"window"`     // norm window in effect, shrunk on short histories
+	Samples    int     `json:"samples"`    // fewest bars a sub-score was ranked against
+	Components int     `json:"components"` // sub-scores in the aggregate
+	Coverage   float64 `json:"coverage"`   // share of the total weight present, 0-1
+	Confidence string  `json:"confidence"` // high, medium or low
+}
+
+// Subscores returns the normalized sub-scores keyed by component id
+func (r ScoreResult) Subscores() map[string]float64 {
+	return map[string]float64{
+		"trend":      r.Values.Trend,
+		"momentum":   r.Values.Momentum,
+		"rsi":        r.Values.RSI,
+		"macd":       r.Values.MACD,
+		"drawdown":   r.Values.Drawdown,
+		"volatility": r.Values.Vol,
+		"mfi":        r.Values.MFI,
+		"bb_pct_b":   r.Values.BB,
+	}
 }
 
 type Component struct {
@@ -57,19 +126,295 @@ type Component struct {
 	Weight      float64 `json:"weight"`
 }
 
+// LabelScheme maps scores to labels. Bands are in ascending order: a score
+// below a band's Upper falls in it, or at it for an inclusive band, the last
+// band takes the rest.
+type LabelScheme struct {
+	ID         string      `json:"id"`
+	Bands      []LabelBand `json:"bands"`
+	Hysteresis float64     `json:"hysteresis,omitempty"` // points a score must clear a cut-off by to move the label
+	MinDwell   int         `json:"min_dwell,omitempty"`  // bars a score must stay out of the band before the label moves
+}
+
+type LabelBand struct {
+	Key       string            `json:"key"`
+	Upper     float64           `json:"upper,omitempty"`     // exclusive unless Inclusive, unused on the last band
+	Inclusive bool              `json:"inclusive,omitempty"` // Upper itself falls in this band, not the next
+	Names     map[string]string `json:"names,omitempty"`     // per language, else the catalog's label.<key>
+}
+
+// LabelSchemeInfo reports the scheme the labels of a response come from
+type LabelSchemeInfo struct {
+	ID         string      `json:"id"`
+	Hysteresis float64     `json:"hysteresis"`
+	MinDwell   int         `json:"min_dwell"`
+	Bands      []BandRange `json:"bands"`
+}
+
+// BandRange is one band of a scheme, scores from From to To. To belongs to
+// the band if Inclusive, else to the next one.
+type BandRange struct {
+	Key       string  `json:"key"`
+	Name      string  `json:"name"`
+	From      float64 `json:"from"`
+	To        float64 `json:"to"`
+	Inclusive bool    `json:"inclusive,omitempty"`
+}
+
+// Divergence is price and an indicator disagreeing between two swing pivots:
+// bearish when price made a higher high and the indicator a lower one, bullish
+// for a lower low against a higher one.
+type Divergence struct {
+	Kind        string  `json:"kind"`
+	Indicator   string  `json:"indicator"` // score or a component id
+	Start       string  `json:"start"`
+	End         string  `json:"end"`
+	Confirmed   string  `json:"confirmed"` // bar the end pivot is confirmed on, pivot bars after end
+	StartPrice  float64 `json:"start_price"`
+	EndPrice    float64 `json:"end_price"`
+	StartValue  float64 `json:"start_value"`
+	EndValue    float64 `json:"end_value"`
+	PriceChange float64 `json:"price_change"` // relative, 0.05 is +5%
+	Strength    float64 `json:"strength"`     // points the indicator moved against price
+}
+
+// RegimeSpan is a run of bars in one market regime, Start and End inclusive
+type RegimeSpan struct {
+	Regime string `json:"regime"`
+	Name   string `json:"name"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Bars   int    `json:"bars"`
+}
+
+// FusionInfo reports the timeframes blended into a fused score
+type FusionInfo struct {
+	Timeframes []TimeframeWeight `json:"timeframes"`
+}
+
+type TimeframeWeight struct {
+	Freq   string  `json:"freq"`
+	Weight float64 `json:"weight"` // share of the blend, weights add up to 1
+}
+
+// SmoothingInfo reports the signal line of a response
+type SmoothingInfo struct {
+	Method string `json:"method"`
+	Period int    `json:"period"`
+}
+
+// Method explains how the score is built
+type Method struct {
+	ReferenceWindow int    `json:"reference_window_trading_days"`
+	Normalize       string `json:"normalize"`
+	Aggregate       string `json:"aggregate"`
+}
+
+// APIResponse is the body of GET /api/v1/fear-greed
 type APIResponse struct {
-	Ticker          string                 `json:"ticker"`
-	Frequency       string                 `json:"frequency"`
-	Latest          *SimpleScore           `json:"latest"`
-	Series          []SimpleScore          `json:"series"`
-	Method          map[string]interface{} `json:"method"`
-	Components      []Component            `json:"components"`
-	LatestSubscores map[string]float64     `json:"latest_subscores"`
+	Ticker          string             `json:"ticker"`
+	Frequency       string             `json:"frequency"`
+	Latest          *SimpleScore       `json:"latest"`
+	Series          []SeriesPoint      `json:"series"`
+	Method          Method             `json:"method"`
+	Components      []Component        `json:"components"`
+	LabelScheme     LabelSchemeInfo    `json:"label_scheme"`
+	Smoothing       *SmoothingInfo     `json:"smoothing,omitempty"`
+	Divergences     []Divergence       `json:"divergences,omitempty"`
+	Regimes         []RegimeSpan       `json:"regimes"`
+	Fusion          *FusionInfo        `json:"fusion,omitempty"`
+	LatestSubscores map[string]float64 `json:"latest_subscores"`
 }
 
 type SimpleScore struct {
-	Date  string  `json:"date"`
-	Score float64 `json:"score"`
-	Label string  `json:"label"`
-	Price float64 `json:"price"` // Added Price
+	Date   string  `json:"date"`
+	Score  float64 `json:"score"`
+	Label  string  `json:"label"`
+	Band   string  `json:"band"`
+	Regime string  `json:"regime"`
+	Price  float64 `json:"price"` // Added Price
+
+	Signal *float64 `json:"signal,omitempty"`
+
+	// With several timeframes
+	Fused      *float64           `json:"fused,omitempty"`
+	Timeframes map[string]float64 `json:"timeframes,omitempty"`
+
+	Quality *DataQuality `json:"quality,omitempty"`
+}
+
+// SeriesPoint is one bar of a score series. Score is null until the
+// normalization window has filled.
+type SeriesPoint struct {
+	Date   string   `json:"date"`
+	Score  *float64 `json:"score"`
+	Label  string   `json:"label"`
+	Band   string   `json:"band"`   // label band key, "" without a score
+	Regime string   `json:"regime"` // market regime, "" until it can be told
+	Price  float64  `json:"price"`
+
+	// With smoothing: the signal line and the crossover on this bar, if any
+	Signal *float64 `json:"signal,omitempty"`
+	Event  string   `json:"event,omitempty"`
+
+	// With several timeframes: the score of each known at this bar's close,
+	// and their weighted blend
+	Fused      *float64           `json:"fused,omitempty"`
+	Timeframes map[string]float64 `json:"timeframes,omitempty"`
+
+	// How much data the score rests on, null without a score
+	Quality *DataQuality `json:"quality,omitempty"`
+}
+
+// PublishedScore is one archived score as the scheduler published it
+type PublishedScore struct {
+	Date        string             `json:"date"`
+	Score       float64            `json:"score"`
+	Label       string             `json:"label"`
+	Price       float64            `json:"price"`
+	Subscores   map[string]float64 `json:"subscores"`
+	NormWindow  int                `json:"norm_window"`
+	Bars        int                `json:"bars"`
+	PublishedAt time.Time          `json:"published_at"`
+}
+
+// HistoryResponse is the body of GET /api/v1/fear-greed/history
+type HistoryResponse struct {
+	Ticker    string           `json:"ticker"`
+	Frequency string           `json:"frequency"`
+	Series    []PublishedScore `json:"series"`
+}
+
+// PointInTimeScore is the score of one bar computed from the data known then
+type PointInTimeScore struct {
+	Date      string              `json:"date"`
+	Score     *float64            `json:"score"`
+	Label     string              `json:"label"`
+	Price     float64             `json:"price"`
+	Subscores map[string]*float64 `json:"subscores"`
+}
+
+// PointInTimeResponse is the body of GET /api/v1/fear-greed/point-in-time
+type PointInTimeResponse struct {
+	Ticker    string             `json:"ticker"`
+	Frequency string             `json:"frequency"`
+	Window    int                `json:"window"`
+	Series    []PointInTimeScore `json:"series"`
+}
+
+// ScoreQuery is the JSON form of the /api/v1/fear-greed query parameters.
+// Empty fields fall back to the batch defaults, then to the server defaults.
+type ScoreQuery struct {
+	Ticker string `json:"ticker,omitempty"`
+	Freq   string `json:"freq,omitempty"`
+	Start  string `json:"start,omitempty"`
+	End    string `json:"end,omitempty"`
+	Asof   string `json:"asof,omitempty"`
+	Window int    `json:"window,omitempty"`
+	Tail   int    `json:"tail,omitempty"`
+	Lang   string `json:"lang,omitempty"`
+	Labels string `json:"labels,omitempty"`
+
+	Smooth       string `json:"smooth,omitempty"`
+	SmoothPeriod int    `json:"smooth_period,omitempty"`
+
+	Divergence string `json:"divergence,omitempty"`
+	Pivot      int    `json:"pivot,omitempty"`
+
+	MTF        bool   `json:"mtf,omitempty"`
+	Timeframes string `json:"timeframes,omitempty"`
+}
+
+// BatchRequest is the body of POST /api/v1/scores:batch. Tickers is shorthand
+// for items that only set a ticker.
+type BatchRequest struct {
+	Tickers  []string     `json:"tickers,omitempty"`
+	Items    []ScoreQuery `json:"items,omitempty"`
+	Defaults ScoreQuery   `json:"defaults"`
+}
+
+// BatchItem is the outcome of one batch entry: either Result or Error is set.
+type BatchItem struct {
+	Index  int          `json:"index"`
+	Ticker string       `json:"ticker"`
+	Result *APIResponse `json:"result,omitempty"`
+	Error  *ErrorBody   `json:"error,omitempty"`
+}
+
+// BatchResponse is the body of POST /api/v1/scores:batch, items in request order
+type BatchResponse struct {
+	Items []BatchItem `json:"items"`
+}
+
+// StreamEvent is the data of a "score" event on GET /api/v1/stream
+type StreamEvent struct {
+	Ticker    string             `json:"ticker"`
+	Frequency string             `json:"frequency"`
+	Date      string             `json:"date"`
+	Score     *float64           `json:"score"`
+	Label     string             `json:"label"`
+	Band      string             `json:"band"`
+	Regime    string             `json:"regime"`
+	Price     float64            `json:"price"`
+	Subscores map[string]float64 `json:"subscores,omitempty"`
+
+	Confidence string `json:"confidence,omitempty"` // high, medium or low, with a score only
+}
+
+// HealthResponse is the body of GET /livez, /readyz and /healthz
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"` // readiness: dependency -> "ok" or the error
+}
+
+// CacheKeysResponse is the body of GET /admin/cache
+type CacheKeysResponse struct {
+	Count     int        `json:"count"` // matching keys, Keys may hold fewer
+	Truncated bool       `json:"truncated,omitempty"`
+	Keys      []CacheKey `json:"keys"`
+}
+
+type CacheKey struct {
+	Key        string  `json:"key"`
+	TTLSeconds float64 `json:"ttl_seconds"` // -1 never expires, 0 just expired
+}
+
+// CachePurgeResponse is the body of DELETE /admin/cache
+type CachePurgeResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// Error codes of the error envelope
+const (
+	ErrInvalidParameter = "invalid_parameter"
+	ErrNotFound         = "not_found"
+	ErrUnauthorized     = "unauthorized"
+	ErrRateLimited      = "rate_limited"
+	ErrUnavailable      = "unavailable"
+	ErrInternal         = "internal"
+)
+
+// ErrorResponse is the envelope of every API error
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Snapshot is an archived "as published" score for one bar
+type Snapshot struct {
+	Ticker      string             `json:"ticker"`
+	Frequency   string             `json:"frequency"`
+	Date        time.Time          `json:"date"`
+	Score       float64            `json:"score"`
+	Labels      map[string]string  `json:"labels"`
+	Band        string             `json:"band,omitempty"`
+	Price       float64            `json:"price"`
+	Subscores   map[string]float64 `json:"subscores"`
+	NormWindow  int                `json:"norm_window"`
+	Bars        int                `json:"bars"`
+	PublishedAt time.Time          `json:"published_at"`
 }
@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/archive"
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/calendar"
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+	"github.com/iwanlebron/stock-analysis/internal/logging"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Scheduler publishes the official daily score for a set of tickers shortly after
+// each market close and writes it to the archive.
+type Scheduler struct {
+	Provider models.Provider
+	Store    *archive.Store
+	Tickers  []string
+	Window   int           // normalization window, defaults to calc.DefaultConfig
+	Delay    time.Duration // wait after the close so the provider has the final bar
+	Langs    []string      // labels to publish, all locales by default
+}
+
+const freq = "1d"
+
+// A failed snapshot is retried after retryBase, doubling each time, up to
+// maxRetries times. After that the session is lost: the next close publishes
+// the next one.
+var retryBase = time.Minute
+
+const maxRetries = 6
+
+// Run blocks until ctx is cancelled. On start it catches up on the latest
+// completed session for every ticker, then sleeps until the next close, or
+// the next retry of a snapshot that failed. Cancelling ctx lets a snapshot
+// already running finish, so a shutdown right after the close still
+// publishes.
+func (s *Scheduler) Run(ctx context.Context) {
+	next := make(map[string]time.Time, len(s.Tickers))
+	failed := make(map[string]int, len(s.Tickers))
+	run := func(t string, now time.Time) {
+		err := s.snapshot(ctx, t, now)
+		if err != nil && failed[t] < maxRetries {
+			wait := retryBase << failed[t]
+			failed[t]++
+			next[t] = now.Add(wait)
+			slog.InfoContext(ctx, "retrying snapshot", "ticker", t, "attempt", failed[t], "in", wait)
+			return
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "giving up on snapshot until the next close", "ticker", t)
+		}
+		failed[t] = 0
+		next[t] = calendar.ExchangeFor(t).NextClose(now).Add(s.Delay)
+	}
+
+	now := time.Now()
+	for _, t := range s.Tickers {
+		if ctx.Err() != nil {
+			return
+		}
+		run(t, now)
+	}
+
+	for {
+		wake := time.Time{}
+		for _, at := range next {
+			if wake.IsZero() || at.Before(wake) {
+				wake = at
+			}
+		}
+		if wake.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(wake))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		for _, t := range s.Tickers {
+			if next[t].After(now) {
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			run(t, now)
+		}
+	}
+}
+
+func (s *Scheduler) snapshot(ctx context.Context, ticker string, now time.Time) error {
+	// Each run is its own trace, so its fetch and compute lines can be found together
+	ctx = logging.WithTrace(context.WithoutCancel(ctx), logging.StartSpan(""))
+	published, err := s.Snapshot(ctx, ticker, now)
+	if err != nil {
+		slog.WarnContext(ctx, "snapshot failed", "ticker", ticker, "err", err)
+		return err
+	}
+	if published != nil {
+		slog.InfoContext(ctx, "published score", "ticker", ticker, "date", published.Date.Format("2006-01-02"), "score", published.Score)
+	}
+	return nil
+}
+
+// Snapshot computes the score of the latest session completed before now and
+// archives it. It returns nil if that session was already published.
+func (s *Scheduler) Snapshot(ctx context.Context, ticker string, now time.Time) (*models.Snapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	ex := calendar.ExchangeFor(ticker)
+	// The session whose close we last passed. For crypto the 00:00 UTC close
+	// belongs to the previous day.
+	lastClose := ex.NextClose(now.AddDate(0, 0, -7))
+	for {
+		c := ex.NextClose(lastClose)
+		if c.After(now.Add(-s.Delay)) {
+			break
+		}
+		lastClose = c
+	}
+	session := lastClose.Add(-time.Nanosecond).In(ex.Location)
+	sessionDay := time.Date(session.Year(), session.Month(), session.Day(), 0, 0, 0, 0, ex.Location)
+
+	// Same warmup buffer as the interactive endpoint
+	pf, err := s.Provider.GetPrices(ctx, ticker, now.AddDate(-2, 0, 0), time.Time{}, freq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop any bar that belongs to a session that has not closed yet
+	bars := pf.Prices
+	for len(bars) > 0 {
+		d := bars[len(bars)-1].Date.In(ex.Location)
+		day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, ex.Location)
+		if !day.After(sessionDay) {
+			break
+		}
+		bars = bars[:len(bars)-1]
+	}
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no completed bars for %s", ticker)
+	}
+	pf = &models.PriceFrame{Ticker: pf.Ticker, Frequency: pf.Frequency, Prices: bars}
+
+	cfg := calc.DefaultConfig
+	if s.Window > 0 {
+		cfg.NormWindow = s.Window
+	}
+	results := calc.Compute(pf, cfg, "zh")
+	last := results[len(results)-1]
+	if math.IsNaN(last.Score) {
+		return nil, fmt.Errorf("not enough history for %s (%d bars)", ticker, len(bars))
+	}
+
+	langs := s.Langs
+	if len(langs) == 0 {
+		langs = i18n.Locales()
+	}
+	labels := make(map[string]string, len(langs))
+	for _, l := range langs {
+		labels[l] = calc.BandLabel(last.Band, l)
+	}
+
+	subscores := make(map[string]float64)
+	for k, v := range last.Subscores() {
+		if !math.IsNaN(v) {
+			subscores[k] = v
+		}
+	}
+
+	snap := models.Snapshot{
+		Ticker:      ticker,
+		Frequency:   freq,
+		Date:        last.Date,
+		Score:       last.Score,
+		Labels:      labels,
+		Band:        last.Band,
+		Price:       last.Price,
+		Subscores:   subscores,
+		NormWindow:  last.Quality.Window, // shrunk on a short history
+		Bars:        len(bars),
+		PublishedAt: now.UTC(),
+	}
+	ok, err := s.Store.Append(snap)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &snap, nil
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/archive"
+	"github.com/iwanlebron/stock-analysis/internal/data/fake"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// flakyProvider fails the first fails fetches, then serves fake bars.
+type flakyProvider struct {
+	fake.Provider
+	fails int32
+	calls atomic.Int32
+}
+
+func (p *flakyProvider) GetPrices(ctx context.Context, ticker string, start, end time.Time, freq string) (*models.PriceFrame, error) {
+	if p.calls.Add(1) <= p.fails {
+		return nil, errors.New("provider down")
+	}
+	return p.Provider.GetPrices(ctx, ticker, start, end, freq)
+}
+
+func openStore(t *testing.T) *archive.Store {
+	t.Helper()
+	store, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// The snapshot records the window the score was normalized over, which is
+// the history itself when it is shorter than the configured one.
+func TestSnapshotNormWindow(t *testing.T) {
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		window int
+		want   func(bars int) int
+	}{
+		{0, func(int) int { return 252 }},
+		{5000, func(bars int) int { return bars }},
+	}
+	for _, tt := range tests {
+		s := &Scheduler{Provider: &fake.Provider{Now: func() time.Time { return now }}, Store: openStore(t), Window: tt.window}
+		snap, err := s.Snapshot(context.Background(), "AAPL", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := tt.want(snap.Bars); snap.NormWindow != want {
+			t.Errorf("window %d: norm_window %d, want %d", tt.window, snap.NormWindow, want)
+		}
+	}
+}
+
+func TestRunRetries(t *testing.T) {
+	defer func(d time.Duration) { retryBase = d }(retryBase)
+	retryBase = 5 * time.Millisecond
+
+	p := &flakyProvider{fails: 3}
+	store := openStore(t)
+	s := &Scheduler{Provider: p, Store: store, Tickers: []string{"AAPL"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		snaps, err := store.History("AAPL", freq, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(snaps) > 0 {
+			if n := p.calls.Load(); n != 4 {
+				t.Errorf("%d fetches, want 3 failed and 1 published", n)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("nothing published after %d fetches", p.calls.Load())
+}
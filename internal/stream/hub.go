@@ -0,0 +1,192 @@
+// Package stream keeps the latest score of watched tickers fresh and fans it out
+// to any number of subscribers, with one refresh loop per ticker.
+package stream
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/logging"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Update is the newest bar of a feed.
+type Update struct {
+	Ticker string
+	Freq   string
+	Result models.ScoreResult
+}
+
+// Hub polls the provider for every ticker somebody is subscribed to. However
+// many clients watch AAPL, there is one refresh every Interval.
+type Hub struct {
+	Provider models.Provider
+	Interval time.Duration
+	Config   calc.Config
+
+	mu    sync.Mutex
+	feeds map[feedKey]*feed
+}
+
+type feedKey struct{ ticker, freq string }
+
+type feed struct {
+	subs   map[chan Update]struct{}
+	last   *Update
+	cancel context.CancelFunc
+}
+
+// NewHub returns a hub refreshing every interval, scoring with cfg.
+func NewHub(p models.Provider, interval time.Duration, cfg calc.Config) *Hub {
+	return &Hub{
+		Provider: p,
+		Interval: interval,
+		Config:   cfg,
+		feeds:    make(map[feedKey]*feed),
+	}
+}
+
+// Subscribe starts receiving updates for ticker. The latest known update, if
+// any, is delivered right away. Slow receivers only ever miss intermediate
+// updates, never the newest one. Call the returned func to unsubscribe.
+func (h *Hub) Subscribe(ticker, freq string) (<-chan Update, func()) {
+	k := feedKey{strings.ToUpper(ticker), freq}
+	ch := make(chan Update, 1)
+
+	h.mu.Lock()
+	f, ok := h.feeds[k]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		ctx = logging.WithTrace(ctx, logging.StartSpan(""))
+		f = &feed{subs: make(map[chan Update]struct{}), cancel: cancel}
+		h.feeds[k] = f
+		go h.run(ctx, k, f)
+	}
+	f.subs[ch] = struct{}{}
+	if f.last != nil {
+		ch <- *f.last
+	}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(f.subs, ch)
+			if len(f.subs) == 0 {
+				// Last viewer gone, stop polling
+				f.cancel()
+				delete(h.feeds, k)
+			}
+		})
+	}
+}
+
+// Feeds is the number of tickers currently refreshed.
+func (h *Hub) Feeds() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.feeds)
+}
+
+func (h *Hub) run(ctx context.Context, k feedKey, f *feed) {
+	var eng *calc.Engine
+	t := time.NewTimer(0)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		var err error
+		eng, err = h.refresh(ctx, k, eng)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.WarnContext(ctx, "stream refresh failed", "ticker", k.ticker, "freq", k.freq, "err", err)
+			}
+		} else if last, ok := eng.Last(); ok {
+			h.publish(f, Update{Ticker: k.ticker, Freq: k.freq, Result: last})
+		}
+		t.Reset(h.Interval)
+	}
+}
+
+// refresh seeds the engine with the full history on the first call. Afterwards
+// it only fetches from the last bar on: that bar, possibly still in progress,
+// is updated in place and newer ones are appended, so nothing is recomputed.
+func (h *Hub) refresh(ctx context.Context, k feedKey, eng *calc.Engine) (*calc.Engine, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if eng == nil || eng.Len() == 0 {
+		pf, err := h.Provider.GetPrices(ctx, k.ticker, lookback(time.Now(), k.freq, h.Config), time.Time{}, k.freq)
+		if err != nil {
+			return eng, err
+		}
+		eng = calc.NewEngine(h.Config, "en")
+		eng.Seed(pf.Prices)
+		return eng, nil
+	}
+
+	last, _ := eng.Last()
+	fresh, err := h.Provider.GetPrices(ctx, k.ticker, last.Date, time.Time{}, k.freq)
+	if err != nil {
+		return eng, err
+	}
+	for _, p := range fresh.Prices {
+		switch {
+		case p.Date.Equal(last.Date):
+			last = eng.Update(p)
+		case p.Date.After(last.Date):
+			last = eng.Push(p)
+		}
+	}
+	return eng, nil
+}
+
+// publish sends u to every subscriber unless nothing changed since the last one.
+func (h *Hub) publish(f *feed, u Update) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if f.last != nil && same(f.last.Result, u.Result) {
+		return
+	}
+	f.last = &u
+	for ch := range f.subs {
+		select {
+		case ch <- u:
+		default:
+			// Drop the stale update still waiting, keep the newest
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- u
+		}
+	}
+}
+
+func same(a, b models.ScoreResult) bool {
+	eq := func(x, y float64) bool { return x == y || (math.IsNaN(x) && math.IsNaN(y)) }
+	return a.Date.Equal(b.Date) && eq(a.Score, b.Score) && eq(a.Price, b.Price)
+}
+
+// lookback is how far back the first load goes: enough bars to fill the
+// normalization window after indicator warmup.
+func lookback(now time.Time, freq string, cfg calc.Config) time.Time {
+	bars := cfg.NormWindow + max(cfg.DDWindow, cfg.MASlow)
+	if freq == "1d" {
+		// 252 trading days a year
+		return now.AddDate(0, 0, -max(3*365, bars*365/252+30))
+	}
+	// About 7 trading hours a day
+	return now.AddDate(0, 0, -max(90, bars*365/252/7+30))
+}
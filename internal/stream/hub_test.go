@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/data/fake"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// countingProvider counts the fetches per ticker.
+type countingProvider struct {
+	fake.Provider
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (p *countingProvider) GetPrices(ctx context.Context, ticker string, start, end time.Time, freq string) (*models.PriceFrame, error) {
+	p.mu.Lock()
+	p.calls[ticker]++
+	p.mu.Unlock()
+	return p.Provider.GetPrices(ctx, ticker, start, end, freq)
+}
+
+func (p *countingProvider) count(ticker string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls[ticker]
+}
+
+func TestHubSharesFetches(t *testing.T) {
+	const (
+		subs     = 10
+		interval = 50 * time.Millisecond
+	)
+	p := &countingProvider{calls: make(map[string]int)}
+	h := NewHub(p, interval, calc.DefaultConfig)
+
+	start := time.Now()
+	var unsubs []func()
+	for i := 0; i < subs; i++ {
+		ch, unsub := h.Subscribe("aapl", "1d")
+		unsubs = append(unsubs, unsub)
+		select {
+		case u := <-ch:
+			if u.Ticker != "AAPL" || u.Freq != "1d" {
+				t.Fatalf("update for %s %s, want AAPL 1d", u.Ticker, u.Freq)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("subscriber %d got no update", i)
+		}
+	}
+	if n := h.Feeds(); n != 1 {
+		t.Fatalf("Feeds() = %d, want 1", n)
+	}
+
+	time.Sleep(4 * interval)
+	// One fetch per interval at most, plus the first load and one in flight
+	if got, max := p.count("AAPL"), int(time.Since(start)/interval)+2; got > max {
+		t.Errorf("%d subscribers made %d fetches, want at most %d", subs, got, max)
+	}
+
+	for _, unsub := range unsubs[1:] {
+		unsub()
+	}
+	if n := h.Feeds(); n != 1 {
+		t.Fatalf("Feeds() = %d with one subscriber left, want 1", n)
+	}
+	unsubs[0]()
+	unsubs[0]() // twice is harmless
+	if n := h.Feeds(); n != 0 {
+		t.Fatalf("Feeds() = %d after the last unsubscribe, want 0", n)
+	}
+
+	// Let a refresh in flight finish, then nothing should poll anymore
+	time.Sleep(interval)
+	stopped := p.count("AAPL")
+	time.Sleep(4 * interval)
+	if got := p.count("AAPL"); got != stopped {
+		t.Errorf("%d fetches after the last unsubscribe, want none", got-stopped)
+	}
+}
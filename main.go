@@ -1,32 +1,193 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
-	"stock-analysis/internal/api"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
+	_ "time/tzdata" // exchange calendars need zoneinfo, alpine image has none
+
+	"google.golang.org/grpc"
+
+	"github.com/iwanlebron/stock-analysis/internal/api"
+	"github.com/iwanlebron/stock-analysis/internal/archive"
+	"github.com/iwanlebron/stock-analysis/internal/barstore"
+	"github.com/iwanlebron/stock-analysis/internal/cache"
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/cli"
+	"github.com/iwanlebron/stock-analysis/internal/config"
+	"github.com/iwanlebron/stock-analysis/internal/logging"
+	"github.com/iwanlebron/stock-analysis/internal/metrics"
+	"github.com/iwanlebron/stock-analysis/internal/scheduler"
 )
 
 func main() {
-	port := "8000"
-	if p := os.Getenv("PORT"); p != "" {
-		port = p
+	// Anything but a subcommand first: serve, so `./server -port 9000` keeps
+	// working and a stray word is reported by the flag parsing
+	args := os.Args[1:]
+	if len(args) > 0 && cli.IsCommand(args[0]) {
+		os.Exit(cli.Run(args[0], args[1:]))
+	}
+	if len(args) > 0 && args[0] == "serve" {
+		args = args[1:]
+	}
+	serve(args)
+}
+
+func serve(args []string) {
+	// Defaults < config file (-config / CONFIG_FILE) < env < flags
+	cfg, err := config.Load(os.Args[0]+" serve", args, os.Getenv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	logging.Setup(os.Stderr, cfg.Log.Format, cfg.Log.Level)
+	calc.SetLabelSchemes(cfg.Score.LabelSchemes, cfg.Score.Labels)
+	api.SetConfig(cfg)
+
+	// SIGTERM from Kubernetes or Docker, Ctrl-C locally
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var jobs sync.WaitGroup
+
+	provider := metrics.InstrumentProvider(cli.NewProvider(cfg), cfg.Data.Provider)
+	// Probe the provider itself, recording probe bars in the bar store is pointless
+	if t := cfg.Health.ProbeTicker; t != "" {
+		api.AddReadyCheck("provider", api.ProviderCheck(provider, t))
+	}
+
+	// Scores and raw bars, shared between replicas with the redis backend
+	var shared cache.Cache = cache.NewMemory(cfg.Cache.CleanupInterval.D())
+	if cfg.Cache.Backend == "redis" {
+		r, err := cache.NewRedis(cfg.Cache.RedisURL, cfg.Cache.Prefix)
+		if err != nil {
+			fatal("connect cache", err)
+		}
+		shared = r
+		api.AddReadyCheck("cache", func(ctx context.Context) error {
+			_, _, err := r.Get(ctx, "health")
+			return err
+		})
+	}
+	defer shared.Close()
+	api.SetCache(shared)
+	provider = &cache.Provider{Upstream: provider, Cache: shared, TTL: cfg.Cache.BarsTTL.D()}
+
+	// Bar store: keeps every bar revision so asof queries ignore later corrections
+	if dir := cfg.Data.BarStoreDir; dir != "" {
+		store, err := barstore.Open(dir)
+		if err != nil {
+			fatal("open bar store", err)
+		}
+		api.AddReadyCheck("bar_store", func(context.Context) error { return store.Check() })
+		provider = &barstore.Recorder{Upstream: provider, Store: store}
+	}
+	api.SetProvider(provider)
+
+	// Daily score archive, e.g. SNAPSHOT_TICKERS=SPY,QQQ,0700.HK
+	if len(cfg.Archive.SnapshotTickers) > 0 {
+		store, err := archive.Open(cfg.Archive.Dir)
+		if err != nil {
+			fatal("open archive", err)
+		}
+		api.SetArchive(store)
+		api.AddReadyCheck("archive", func(context.Context) error { return store.Check() })
+
+		sched := &scheduler.Scheduler{
+			Provider: provider,
+			Store:    store,
+			Window:   cfg.Score.Window,
+			Delay:    cfg.Archive.Delay.D(),
+		}
+		for _, t := range cfg.Archive.SnapshotTickers {
+			sched.Tickers = append(sched.Tickers, strings.ToUpper(t))
+		}
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			sched.Run(ctx)
+		}()
 	}
 
 	handler := api.Handler()
 	server := &http.Server{
-		Addr:              ":" + port,
+		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:           handler,
-		ReadHeaderTimeout: 5 * time.Second,
-		ReadTimeout:       15 * time.Second,
-		WriteTimeout:      15 * time.Second,
-		IdleTimeout:       60 * time.Second,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout.D(),
+		ReadTimeout:       cfg.Server.ReadTimeout.D(),
+		WriteTimeout:      cfg.Server.WriteTimeout.D(),
+		IdleTimeout:       cfg.Server.IdleTimeout.D(),
 	}
 
-	fmt.Printf("Starting Fear & Greed Server on http://localhost:%s\n", port)
-	if err := server.ListenAndServe(); err != nil {
-		log.Fatal(err)
+	slog.Info("starting Fear & Greed server", "url", fmt.Sprintf("http://localhost:%d", cfg.Server.Port))
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.ListenAndServe() }()
+
+	// gRPC on its own port, same provider, cache and limits
+	var grpcServer *grpc.Server
+	if port := cfg.Server.GRPCPort; port > 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			fatal("listen grpc", err)
+		}
+		grpcServer = api.GRPCServer()
+		slog.Info("starting gRPC server", "addr", lis.Addr().String())
+		go func() { serveErr <- grpcServer.Serve(lis) }()
 	}
+
+	select {
+	case err := <-serveErr:
+		fatal("server stopped", err)
+	case <-ctx.Done():
+	}
+	// A second signal kills right away
+	stop()
+
+	slog.Info("shutting down", "delay", cfg.Server.ShutdownDelay.String(), "timeout", cfg.Server.ShutdownTimeout.String())
+	api.Drain()
+	// Keep serving while the load balancer notices /readyz failing
+	time.Sleep(cfg.Server.ShutdownDelay.D())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.D())
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("shutdown: requests still running", "err", err)
+	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			slog.Warn("shutdown: gRPC calls still running")
+			grpcServer.Stop()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		slog.Info("shutdown complete")
+	case <-shutdownCtx.Done():
+		slog.Warn("shutdown: background jobs still running")
+	}
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
 }
@@ -0,0 +1,52 @@
+package feargreed
+
+import "github.com/iwanlebron/stock-analysis/internal/calc"
+
+// Divergence kinds
+const (
+	Bearish = calc.Bearish // price made a higher high, the indicator a lower high
+	Bullish = calc.Bullish // price made a lower low, the indicator a higher low
+)
+
+// Divergence is price and an indicator disagreeing between two swing pivots.
+// Start and End index the series it was found in. A pivot is only a pivot
+// once the bars after it didn't beat it, so the divergence is known from
+// Confirmed on, not from End.
+type Divergence struct {
+	Kind        string
+	Start, End  int
+	Confirmed   int     // End plus the pivot bars
+	PriceChange float64 // End price over Start price, minus 1, 0 if Start price isn't positive
+	ValueChange float64 // End value minus Start value
+	Strength    float64 // how far the indicator moved against price, in points
+}
+
+// FindDivergences compares consecutive swing highs and lows of price, bars
+// beating the pivot bars on each side, with value at the same bars. Pivots
+// more than maxGap bars apart are not compared. The newest pivot bars can't
+// end a divergence yet, their swing is not confirmed.
+func FindDivergences(price, value []float64, pivot, maxGap int) []Divergence {
+	found := calc.FindDivergences(price, value, calc.DivergenceConfig{Pivot: pivot, MaxGap: maxGap})
+	out := make([]Divergence, len(found))
+	for i, d := range found {
+		out[i] = Divergence(d)
+	}
+	return out
+}
+
+// Divergences finds the divergences of points between the close and the
+// score, or the sub-score c when it is not empty, with the server defaults
+// of 5 pivot bars and at most 60 bars between pivots.
+func Divergences(points []Point, c Component) []Divergence {
+	price := make([]float64, len(points))
+	value := make([]float64, len(points))
+	for i, p := range points {
+		price[i] = p.Price
+		if c == "" {
+			value[i] = p.Score
+		} else {
+			value[i] = p.Subscores[c]
+		}
+	}
+	return FindDivergences(price, value, calc.DefaultDivergence.Pivot, calc.DefaultDivergence.MaxGap)
+}
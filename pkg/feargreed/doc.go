@@ -0,0 +1,51 @@
+// Package feargreed computes a per-ticker Fear & Greed score, from 0 (extreme
+// fear) to 100 (extreme greed), out of daily or intraday OHLCV bars. It is the
+// engine behind the stock-analysis server, without HTTP or data source
+// dependencies: bring your own bars.
+//
+// A score is built in four steps, each also exported on its own:
+//
+//   - indicators: trend, momentum, RSI, MACD, drawdown, volatility, MFI and
+//     Bollinger %B are computed from the bars (SMA, RSI, MACD, ...)
+//   - normalization: every indicator becomes its percentile rank over a rolling
+//     window, 252 bars by default (Normalize)
+//   - aggregation: a weighted mean of the available sub-scores (Aggregate)
+//   - labeling: Extreme Fear, Fear, Neutral, Greed or Extreme Greed (Label),
+//     optionally sticky across bars (WithLabelScheme)
+//
+// On top of the score, Divergences spots price and sentiment disagreeing at
+// swing highs and lows, WithSmoothing adds a signal line, and every Point
+// carries its market regime: trending up or down, range-bound or crisis.
+//
+// Scoring a series:
+//
+//	points, err := feargreed.Compute(bars,
+//		feargreed.WithWindow(126),
+//		feargreed.WithLanguage(feargreed.English),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	last := points[len(points)-1]
+//	fmt.Printf("%s %.1f %s\n", last.Time.Format("2006-01-02"), last.Score, last.Label)
+//
+// Only the latest bar, with custom weights that ignore volume:
+//
+//	w := feargreed.DefaultWeights()
+//	w[feargreed.ComponentMFI] = 0
+//	p, err := feargreed.Latest(bars, feargreed.WithWeights(w))
+//
+// Using the building blocks directly:
+//
+//	rsi := feargreed.RSI(closes, 14)
+//	ranks := feargreed.Normalize(rsi, 252, feargreed.HigherIsGreedier)
+//
+// Scores need warmup: until a bar has a full normalization window of history
+// behind it, its Score and sub-scores are NaN and Point.Valid reports false.
+// Series shorter than the window are normalized over the whole series instead,
+// so a short history still scores its latest bar.
+//
+// The API follows semantic versioning with the module: exported names keep
+// their meaning, new components and options may be added. Sub-scores are
+// keyed by Component so a new component is not a breaking change.
+package feargreed
@@ -0,0 +1,75 @@
+package feargreed_test
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/pkg/feargreed"
+)
+
+// dailyBars is a made-up series of n daily bars: a slow uptrend with swings.
+func dailyBars(n int) []feargreed.Bar {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]feargreed.Bar, n)
+	for i := range bars {
+		x := float64(i)
+		c := 100 + 0.05*x + 8*math.Sin(x/15) + 2*math.Sin(x/4)
+		bars[i] = feargreed.Bar{
+			Time:   start.AddDate(0, 0, i),
+			Open:   c - 0.4,
+			High:   c + 1,
+			Low:    c - 1,
+			Close:  c,
+			Volume: 1e6 + 2e5*math.Cos(x/7),
+		}
+	}
+	return bars
+}
+
+func ExampleCompute() {
+	points, err := feargreed.Compute(dailyBars(300),
+		feargreed.WithWindow(120),
+		feargreed.WithLanguage(feargreed.English),
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, p := range points[len(points)-3:] {
+		fmt.Printf("%s %.1f %-13s %s %s\n", p.Time.Format("2006-01-02"), p.Score, p.Label, p.Regime, p.Quality.Confidence)
+	}
+	fmt.Println("warmup:", !points[0].Valid())
+	// Output:
+	// 2024-10-24 70.5 Greed         trending_up high
+	// 2024-10-25 74.4 Greed         trending_up high
+	// 2024-10-26 76.9 Extreme Greed trending_up high
+	// warmup: true
+}
+
+func ExampleNormalize() {
+	rsi := []float64{30, 50, 40, 70, 60, 20, 80}
+	// Each value ranked among itself and the 3 before it
+	fmt.Printf("%.0f\n", feargreed.Normalize(rsi, 4, feargreed.HigherIsGreedier))
+	// Output:
+	// [NaN NaN NaN 100 75 25 100]
+}
+
+func ExampleWithLabelScheme() {
+	bars := dailyBars(300)
+	five, _ := feargreed.Compute(bars, feargreed.WithWindow(120), feargreed.WithLanguage(feargreed.English))
+	three, _ := feargreed.Compute(bars, feargreed.WithWindow(120), feargreed.WithLanguage(feargreed.English),
+		feargreed.WithLabelScheme("three"))
+	for i := len(bars) - 4; i < len(bars); i++ {
+		fmt.Printf("%.1f %-13s %s\n", five[i].Score, five[i].Label, three[i].Label)
+	}
+
+	_, err := feargreed.Compute(bars, feargreed.WithLabelScheme("seven"))
+	fmt.Println(err)
+	// Output:
+	// 68.2 Greed         Greed
+	// 70.5 Greed         Greed
+	// 74.4 Greed         Greed
+	// 76.9 Extreme Greed Greed
+	// feargreed: unknown label scheme "seven"
+}
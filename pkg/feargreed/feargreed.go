@@ -0,0 +1,204 @@
+package feargreed
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/models"
+)
+
+// Bar is one OHLCV candle. Bars passed to Compute must be sorted by Time.
+type Bar struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// Component identifies one sub-score.
+type Component string
+
+const (
+	ComponentTrend      Component = "trend"      // close against its fast and slow moving averages
+	ComponentMomentum   Component = "momentum"   // return over the momentum window
+	ComponentRSI        Component = "rsi"        // relative strength index
+	ComponentMACD       Component = "macd"       // MACD histogram (12, 26, 9)
+	ComponentDrawdown   Component = "drawdown"   // distance from the rolling high
+	ComponentVolatility Component = "volatility" // annualized realized volatility, calmer is greedier
+	ComponentMFI        Component = "mfi"        // money flow index, needs volume
+	ComponentBollinger  Component = "bb_pct_b"   // position within the Bollinger bands (20, 2)
+)
+
+// Components lists every component in display order.
+func Components() []Component {
+	return []Component{ComponentTrend, ComponentMomentum, ComponentRSI, ComponentMACD, ComponentDrawdown, ComponentVolatility, ComponentMFI, ComponentBollinger}
+}
+
+// DefaultWeights returns a copy of the default component weights.
+func DefaultWeights() map[Component]float64 {
+	w := make(map[Component]float64, len(calc.Weights))
+	for k, v := range calc.Weights {
+		w[Component(k)] = v
+	}
+	return w
+}
+
+// Point is the score of one bar.
+type Point struct {
+	Time  time.Time
+	Price float64 // close
+	Score float64 // 0-100, NaN during warmup
+	Label string  // "-" during warmup
+	Band  string  // key of the label band, e.g. "extreme_fear", "" during warmup
+	// Signal is the smoothed score, NaN without WithSmoothing or during warmup
+	Signal float64
+	// Cross is CrossUp or CrossDown on a bar where the score crossed its signal
+	Cross string
+	// Regime is the market regime of the bar, "" during warmup
+	Regime string
+	// Quality tells how much data the score rests on, zero during warmup
+	Quality Quality
+	// Subscores are the normalized components, 0-100 or NaN during warmup
+	Subscores map[Component]float64
+	// Raw are the indicator values before normalization
+	Raw map[Component]float64
+}
+
+// Market regimes of Point.Regime. A new regime takes over after holding for
+// 3 bars.
+const (
+	RegimeTrendingUp   = calc.RegimeTrendingUp   // close 2% or more above its moving averages, momentum agreeing
+	RegimeTrendingDown = calc.RegimeTrendingDown // close 2% or more below them
+	RegimeRange        = calc.RegimeRange        // neither trending nor in crisis
+	RegimeCrisis       = calc.RegimeCrisis       // volatility in the top and drawdown in the deepest fifth of the window
+)
+
+// Quality is the data behind a score.
+type Quality struct {
+	Window     int     // normalization window in effect, shrunk when the series is shorter
+	Samples    int     // fewest bars a sub-score was ranked against
+	Components int     // sub-scores in the aggregate
+	Coverage   float64 // share of the total weight present, 0-1
+	Confidence string  // ConfidenceHigh, ConfidenceMedium or ConfidenceLow
+}
+
+// Confidence grades of Quality. High needs the full window and at least 80%
+// of the weight, low is under a quarter of the window or half the weight.
+const (
+	ConfidenceHigh   = calc.ConfidenceHigh
+	ConfidenceMedium = calc.ConfidenceMedium
+	ConfidenceLow    = calc.ConfidenceLow
+)
+
+// Valid reports whether the point has a score, i.e. is past the warmup.
+func (p Point) Valid() bool {
+	return !math.IsNaN(p.Score)
+}
+
+// ErrNoBars is returned by Compute and Latest for an empty series.
+var ErrNoBars = errors.New("feargreed: no bars")
+
+// Compute scores every bar. The result has one Point per bar, in order.
+func Compute(bars []Bar, opts ...Option) ([]Point, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 {
+		return nil, ErrNoBars
+	}
+	pf := &models.PriceFrame{Prices: make([]models.Price, len(bars))}
+	for i, b := range bars {
+		if i > 0 && !b.Time.After(bars[i-1].Time) {
+			return nil, fmt.Errorf("feargreed: bar %d at %s is not after the previous bar", i, b.Time.Format(time.RFC3339))
+		}
+		pf.Prices[i] = models.Price{Date: b.Time, Open: b.Open, High: b.High, Low: b.Low, Close: b.Close, Volume: b.Volume}
+	}
+
+	results := calc.Compute(pf, o.cfg, string(o.lang))
+	points := make([]Point, len(results))
+	for i, r := range results {
+		points[i] = point(r)
+	}
+	return points, nil
+}
+
+// Latest scores bars and returns the last point only.
+func Latest(bars []Bar, opts ...Option) (Point, error) {
+	points, err := Compute(bars, opts...)
+	if err != nil {
+		return Point{}, err
+	}
+	return points[len(points)-1], nil
+}
+
+func point(r models.ScoreResult) Point {
+	p := Point{
+		Time:      r.Date,
+		Price:     r.Price,
+		Score:     r.Score,
+		Label:     r.Label,
+		Band:      r.Band,
+		Signal:    r.Signal,
+		Cross:     r.Cross,
+		Regime:    r.Regime,
+		Quality:   Quality(r.Quality),
+		Subscores: make(map[Component]float64, 8),
+		Raw: map[Component]float64{
+			ComponentTrend:      r.Raw.Trend,
+			ComponentMomentum:   r.Raw.Momentum,
+			ComponentRSI:        r.Raw.RSI,
+			ComponentMACD:       r.Raw.MACD,
+			ComponentDrawdown:   r.Raw.Drawdown,
+			ComponentVolatility: r.Raw.Vol,
+			ComponentMFI:        r.Raw.MFI,
+			ComponentBollinger:  r.Raw.BB,
+		},
+	}
+	for k, v := range r.Subscores() {
+		p.Subscores[Component(k)] = v
+	}
+	return p
+}
+
+// Aggregate is the weighted mean of the sub-scores that are present and not
+// NaN, NaN when there are none. nil weights are DefaultWeights. It gives the
+// same Score as Compute for the same sub-scores.
+func Aggregate(subscores, weights map[Component]float64) float64 {
+	if weights == nil {
+		weights = DefaultWeights()
+	}
+	sum, wSum := 0.0, 0.0
+	// Same order as the engine, so the float sum is identical
+	for _, k := range Components() {
+		if v, ok := subscores[k]; ok && !math.IsNaN(v) {
+			sum += v * weights[k]
+			wSum += weights[k]
+		}
+	}
+	if wSum == 0 {
+		return math.NaN()
+	}
+	return sum / wSum
+}
+
+// Language of the labels.
+type Language string
+
+const (
+	Chinese            Language = "zh" // Simplified
+	TraditionalChinese Language = "zh-TW"
+	English            Language = "en"
+	Japanese           Language = "ja"
+)
+
+// Label names the band of a 0-100 score: below 25 Extreme Fear, below 45
+// Fear, up to 55 Neutral, up to 75 Greed, else Extreme Greed. NaN is "-".
+func Label(score float64, lang Language) string {
+	return calc.LabelFromScore(score, string(lang))
+}
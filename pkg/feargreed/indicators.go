@@ -0,0 +1,75 @@
+package feargreed
+
+import "github.com/iwanlebron/stock-analysis/internal/calc"
+
+// Indicators take a series oldest first and return one value per input, NaN
+// where there is not enough history yet.
+
+// SMA is the simple moving average over window values.
+func SMA(values []float64, window int) []float64 {
+	return calc.SMA(values, window)
+}
+
+// EMA is the exponential moving average with smoothing 2/(span+1), seeded
+// with the first value.
+func EMA(values []float64, span int) []float64 {
+	return calc.EMA(values, span)
+}
+
+// Trend is the mean relative distance of each close from its fast and slow
+// simple moving averages.
+func Trend(closes []float64, fast, slow int) []float64 {
+	return calc.Trend(closes, fast, slow)
+}
+
+// Momentum is the return over the last window bars.
+func Momentum(closes []float64, window int) []float64 {
+	return calc.Momentum(closes, window)
+}
+
+// RSI is Wilder's relative strength index, 0-100.
+func RSI(closes []float64, window int) []float64 {
+	return calc.RSI(closes, window)
+}
+
+// MACD is the MACD histogram: EMA(12) - EMA(26), minus its EMA(9) signal.
+func MACD(closes []float64) []float64 {
+	return calc.MACD(closes)
+}
+
+// Drawdown is each close relative to the highest close of the last window
+// bars, 0 at a new high and negative below it.
+func Drawdown(closes []float64, window int) []float64 {
+	return calc.Drawdown(closes, window)
+}
+
+// RealizedVol is the annualized (252 bars) standard deviation of returns over
+// window bars.
+func RealizedVol(closes []float64, window int) []float64 {
+	return calc.RealizedVol(closes, window)
+}
+
+// MFI is the money flow index, 0-100, the volume weighted RSI.
+func MFI(high, low, closes, volume []float64, window int) []float64 {
+	return calc.MFI(high, low, closes, volume, window)
+}
+
+// BollingerPercentB is the close's position within Bollinger bands of window
+// bars and numStdDev deviations: 0 at the lower band, 1 at the upper.
+func BollingerPercentB(closes []float64, window int, numStdDev float64) []float64 {
+	return calc.BollingerPercentB(closes, window, numStdDev)
+}
+
+// Direction tells Normalize which end of an indicator is greedy.
+type Direction int
+
+const (
+	HigherIsGreedier Direction = 1
+	LowerIsGreedier  Direction = -1 // volatility: calm markets are greedy
+)
+
+// Normalize maps an indicator to 0-100: the percentile rank of each value
+// among the last window values. NaN until window values are available.
+func Normalize(values []float64, window int, dir Direction) []float64 {
+	return calc.RollingScore(values, window, int(dir))
+}
@@ -0,0 +1,168 @@
+package feargreed
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/iwanlebron/stock-analysis/internal/calc"
+	"github.com/iwanlebron/stock-analysis/internal/i18n"
+)
+
+// Option configures Compute and Latest.
+type Option func(*options) error
+
+type options struct {
+	cfg  calc.Config
+	lang Language
+}
+
+func newOptions(opts []Option) (options, error) {
+	o := options{cfg: calc.DefaultConfig, lang: English}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
+
+func positive(name string, n int) error {
+	if n <= 0 {
+		return fmt.Errorf("feargreed: %s must be positive, got %d", name, n)
+	}
+	return nil
+}
+
+// WithWindow sets the normalization window in bars, 252 (a trading year of
+// daily bars) by default. Sub-scores rank each indicator within this window.
+func WithWindow(bars int) Option {
+	return func(o *options) error {
+		o.cfg.NormWindow = bars
+		return positive("window", bars)
+	}
+}
+
+// WithMovingAverages sets the fast and slow moving averages of the trend
+// component, 20 and 60 bars by default.
+func WithMovingAverages(fast, slow int) Option {
+	return func(o *options) error {
+		o.cfg.MAFast, o.cfg.MASlow = fast, slow
+		if err := positive("fast moving average", fast); err != nil {
+			return err
+		}
+		return positive("slow moving average", slow)
+	}
+}
+
+// WithMomentumWindow sets the lookback of the momentum component, 20 bars by
+// default.
+func WithMomentumWindow(bars int) Option {
+	return func(o *options) error {
+		o.cfg.MomWindow = bars
+		return positive("momentum window", bars)
+	}
+}
+
+// WithRSIWindow sets the RSI period, 14 by default.
+func WithRSIWindow(bars int) Option {
+	return func(o *options) error {
+		o.cfg.RSIWindow = bars
+		return positive("RSI window", bars)
+	}
+}
+
+// WithVolatilityWindow sets the realized volatility window, 20 bars by default.
+func WithVolatilityWindow(bars int) Option {
+	return func(o *options) error {
+		o.cfg.VolWindow = bars
+		return positive("volatility window", bars)
+	}
+}
+
+// WithDrawdownWindow sets how far back the drawdown component looks for the
+// high, 252 bars by default.
+func WithDrawdownWindow(bars int) Option {
+	return func(o *options) error {
+		o.cfg.DDWindow = bars
+		return positive("drawdown window", bars)
+	}
+}
+
+// WithWeights replaces the component weights, see DefaultWeights. Missing
+// components weigh 0. Weights need not sum to 1, the score is a weighted mean.
+func WithWeights(w map[Component]float64) Option {
+	return func(o *options) error {
+		known := map[Component]bool{}
+		for _, c := range Components() {
+			known[c] = true
+		}
+		weights := make(map[string]float64, len(w))
+		total := 0.0
+		for c, v := range w {
+			if !known[c] {
+				return fmt.Errorf("feargreed: unknown component %q", c)
+			}
+			if v < 0 || math.IsNaN(v) {
+				return fmt.Errorf("feargreed: weight of %s must be a non-negative number", c)
+			}
+			weights[string(c)] = v
+			total += v
+		}
+		if total == 0 {
+			return fmt.Errorf("feargreed: all weights are zero")
+		}
+		o.cfg.Weights = weights
+		return nil
+	}
+}
+
+// WithLabelScheme picks how scores are labeled: "default" (the five bands of
+// Label), "stable" (the same bands, but a label only moves once the score is 3
+// points past a cut-off for 2 bars) or "three" (Fear below 40, Neutral below
+// 60, else Greed).
+func WithLabelScheme(id string) Option {
+	return func(o *options) error {
+		if _, ok := calc.LookupScheme(id); !ok || !calc.IsBuiltinScheme(id) {
+			return fmt.Errorf("feargreed: unknown label scheme %q", id)
+		}
+		o.cfg.Labels = id
+		return nil
+	}
+}
+
+// Smoothing methods of WithSmoothing
+const (
+	SmoothEMA    = calc.SmoothEMA
+	SmoothSMA    = calc.SmoothSMA
+	SmoothKalman = calc.SmoothKalman
+)
+
+// Crossover events of Point.Cross
+const (
+	CrossUp   = calc.CrossUp
+	CrossDown = calc.CrossDown
+)
+
+// WithSmoothing adds a signal line to every point: an EMA, SMA or Kalman
+// filtered score over period bars. The Kalman filter follows the first scores
+// more closely and settles at the EMA of the same period.
+func WithSmoothing(method string, period int) Option {
+	return func(o *options) error {
+		o.cfg.Smoothing = calc.Smoothing{Method: method, Period: period}
+		if err := o.cfg.Smoothing.Validate(); err != nil {
+			return fmt.Errorf("feargreed: %w", err)
+		}
+		return nil
+	}
+}
+
+// WithLanguage sets the language of labels, English by default.
+func WithLanguage(lang Language) Option {
+	return func(o *options) error {
+		if !i18n.Supported(string(lang)) {
+			return fmt.Errorf("feargreed: unsupported language %q", lang)
+		}
+		o.lang = lang
+		return nil
+	}
+}
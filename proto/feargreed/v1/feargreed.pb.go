@@ -0,0 +1,2176 @@
+// Fear & Greed scores over gRPC. The messages mirror the JSON API: dates are
+// exchange-local YYYY-MM-DD strings for daily bars and RFC 3339 for intraday,
+// scores run from 0 (extreme fear) to 100 (extreme greed).
+//
+// Regenerate the Go code with `go generate ./proto/...`, which needs protoc,
+// protoc-gen-go and protoc-gen-go-grpc on the PATH.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: feargreed.proto
+
+package feargreedv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GetScoreRequest takes the query parameters of GET /api/v1/fear-greed. Empty
+// or zero fields use the server defaults.
+type GetScoreRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker       string `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Freq         string `protobuf:"bytes,2,opt,name=freq,proto3" json:"freq,omitempty"`                                       // 1d (default) or 1h
+	Start        string `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`                                     // YYYY-MM-DD
+	End          string `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`                                         // YYYY-MM-DD
+	Asof         string `protobuf:"bytes,5,opt,name=asof,proto3" json:"asof,omitempty"`                                       // YYYY-MM-DD, only data known on that day
+	Window       int32  `protobuf:"varint,6,opt,name=window,proto3" json:"window,omitempty"`                                  // normalization window in bars
+	Tail         int32  `protobuf:"varint,7,opt,name=tail,proto3" json:"tail,omitempty"`                                      // bars returned without start
+	Lang         string `protobuf:"bytes,8,opt,name=lang,proto3" json:"lang,omitempty"`                                       // zh, zh-TW, en or ja
+	Labels       string `protobuf:"bytes,9,opt,name=labels,proto3" json:"labels,omitempty"`                                   // label scheme: default, stable, three or a configured one
+	Smooth       string `protobuf:"bytes,10,opt,name=smooth,proto3" json:"smooth,omitempty"`                                  // signal line: none, ema, sma or kalman
+	SmoothPeriod int32  `protobuf:"varint,11,opt,name=smooth_period,json=smoothPeriod,proto3" json:"smooth_period,omitempty"` // signal line period in bars
+	Divergence   string `protobuf:"bytes,12,opt,name=divergence,proto3" json:"divergence,omitempty"`                          // comma separated indicators checked against price: score or component ids
+	Pivot        int32  `protobuf:"varint,13,opt,name=pivot,proto3" json:"pivot,omitempty"`                                   // bars on each side of a swing pivot, default 5
+	Mtf          bool   `protobuf:"varint,14,opt,name=mtf,proto3" json:"mtf,omitempty"`                                       // blend the configured timeframes into a fused score
+	Timeframes   string `protobuf:"bytes,15,opt,name=timeframes,proto3" json:"timeframes,omitempty"`                          // blend these instead, e.g. 1d:0.5,1wk:0.3,1h:0.2
+}
+
+func (x *GetScoreRequest) Reset() {
+	*x = GetScoreRequest{}
+	mi := &file_feargreed_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScoreRequest) ProtoMessage() {}
+
+func (x *GetScoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScoreRequest.ProtoReflect.Descriptor instead.
+func (*GetScoreRequest) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetScoreRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetFreq() string {
+	if x != nil {
+		return x.Freq
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetAsof() string {
+	if x != nil {
+		return x.Asof
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetWindow() int32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *GetScoreRequest) GetTail() int32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+func (x *GetScoreRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetLabels() string {
+	if x != nil {
+		return x.Labels
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetSmooth() string {
+	if x != nil {
+		return x.Smooth
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetSmoothPeriod() int32 {
+	if x != nil {
+		return x.SmoothPeriod
+	}
+	return 0
+}
+
+func (x *GetScoreRequest) GetDivergence() string {
+	if x != nil {
+		return x.Divergence
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetPivot() int32 {
+	if x != nil {
+		return x.Pivot
+	}
+	return 0
+}
+
+func (x *GetScoreRequest) GetMtf() bool {
+	if x != nil {
+		return x.Mtf
+	}
+	return false
+}
+
+func (x *GetScoreRequest) GetTimeframes() string {
+	if x != nil {
+		return x.Timeframes
+	}
+	return ""
+}
+
+// SeriesPoint is one bar. score is unset until the normalization window has
+// filled.
+type SeriesPoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Date       string             `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Score      *float64           `protobuf:"fixed64,2,opt,name=score,proto3,oneof" json:"score,omitempty"`
+	Label      string             `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Price      float64            `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Band       string             `protobuf:"bytes,5,opt,name=band,proto3" json:"band,omitempty"`                                                                                                        // label band key, e.g. extreme_fear, empty without a score
+	Signal     *float64           `protobuf:"fixed64,6,opt,name=signal,proto3,oneof" json:"signal,omitempty"`                                                                                            // smoothed score, with smoothing only
+	Event      string             `protobuf:"bytes,7,opt,name=event,proto3" json:"event,omitempty"`                                                                                                      // cross_up or cross_down when the score crossed its signal
+	Regime     string             `protobuf:"bytes,8,opt,name=regime,proto3" json:"regime,omitempty"`                                                                                                    // trending_up, trending_down, range or crisis, empty in warmup
+	Fused      *float64           `protobuf:"fixed64,9,opt,name=fused,proto3,oneof" json:"fused,omitempty"`                                                                                              // with timeframes: their weighted blend
+	Timeframes map[string]float64 `protobuf:"bytes,10,rep,name=timeframes,proto3" json:"timeframes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"` // score of each timeframe known at the close of this bar
+	Quality    *DataQuality       `protobuf:"bytes,11,opt,name=quality,proto3" json:"quality,omitempty"`                                                                                                 // unset without a score
+}
+
+func (x *SeriesPoint) Reset() {
+	*x = SeriesPoint{}
+	mi := &file_feargreed_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeriesPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeriesPoint) ProtoMessage() {}
+
+func (x *SeriesPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeriesPoint.ProtoReflect.Descriptor instead.
+func (*SeriesPoint) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SeriesPoint) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *SeriesPoint) GetScore() float64 {
+	if x != nil && x.Score != nil {
+		return *x.Score
+	}
+	return 0
+}
+
+func (x *SeriesPoint) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *SeriesPoint) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *SeriesPoint) GetBand() string {
+	if x != nil {
+		return x.Band
+	}
+	return ""
+}
+
+func (x *SeriesPoint) GetSignal() float64 {
+	if x != nil && x.Signal != nil {
+		return *x.Signal
+	}
+	return 0
+}
+
+func (x *SeriesPoint) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *SeriesPoint) GetRegime() string {
+	if x != nil {
+		return x.Regime
+	}
+	return ""
+}
+
+func (x *SeriesPoint) GetFused() float64 {
+	if x != nil && x.Fused != nil {
+		return *x.Fused
+	}
+	return 0
+}
+
+func (x *SeriesPoint) GetTimeframes() map[string]float64 {
+	if x != nil {
+		return x.Timeframes
+	}
+	return nil
+}
+
+func (x *SeriesPoint) GetQuality() *DataQuality {
+	if x != nil {
+		return x.Quality
+	}
+	return nil
+}
+
+// Score is a scored bar with its normalized components, keyed by component
+// id (trend, momentum, rsi, macd, drawdown, volatility, mfi, bb_pct_b).
+type Score struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Date       string             `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Score      float64            `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	Label      string             `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Price      float64            `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Subscores  map[string]float64 `protobuf:"bytes,5,rep,name=subscores,proto3" json:"subscores,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Band       string             `protobuf:"bytes,6,opt,name=band,proto3" json:"band,omitempty"`             // label band key, unset in history
+	Signal     *float64           `protobuf:"fixed64,7,opt,name=signal,proto3,oneof" json:"signal,omitempty"` // smoothed score, with smoothing only
+	Regime     string             `protobuf:"bytes,8,opt,name=regime,proto3" json:"regime,omitempty"`         // market regime, unset in history
+	Fused      *float64           `protobuf:"fixed64,9,opt,name=fused,proto3,oneof" json:"fused,omitempty"`
+	Timeframes map[string]float64 `protobuf:"bytes,10,rep,name=timeframes,proto3" json:"timeframes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Quality    *DataQuality       `protobuf:"bytes,11,opt,name=quality,proto3" json:"quality,omitempty"` // unset in history
+}
+
+func (x *Score) Reset() {
+	*x = Score{}
+	mi := &file_feargreed_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Score) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Score) ProtoMessage() {}
+
+func (x *Score) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Score.ProtoReflect.Descriptor instead.
+func (*Score) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Score) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Score) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Score) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Score) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Score) GetSubscores() map[string]float64 {
+	if x != nil {
+		return x.Subscores
+	}
+	return nil
+}
+
+func (x *Score) GetBand() string {
+	if x != nil {
+		return x.Band
+	}
+	return ""
+}
+
+func (x *Score) GetSignal() float64 {
+	if x != nil && x.Signal != nil {
+		return *x.Signal
+	}
+	return 0
+}
+
+func (x *Score) GetRegime() string {
+	if x != nil {
+		return x.Regime
+	}
+	return ""
+}
+
+func (x *Score) GetFused() float64 {
+	if x != nil && x.Fused != nil {
+		return *x.Fused
+	}
+	return 0
+}
+
+func (x *Score) GetTimeframes() map[string]float64 {
+	if x != nil {
+		return x.Timeframes
+	}
+	return nil
+}
+
+func (x *Score) GetQuality() *DataQuality {
+	if x != nil {
+		return x.Quality
+	}
+	return nil
+}
+
+// DataQuality tells how much data a score rests on.
+type DataQuality struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Window     int32   `protobuf:"varint,1,opt,name=window,proto3" json:"window,omitempty"`         // norm window in effect, shrunk on short histories
+	Samples    int32   `protobuf:"varint,2,opt,name=samples,proto3" json:"samples,omitempty"`       // fewest bars a sub-score was ranked against
+	Components int32   `protobuf:"varint,3,opt,name=components,proto3" json:"components,omitempty"` // sub-scores in the aggregate
+	Coverage   float64 `protobuf:"fixed64,4,opt,name=coverage,proto3" json:"coverage,omitempty"`    // share of the total weight present, 0-1
+	Confidence string  `protobuf:"bytes,5,opt,name=confidence,proto3" json:"confidence,omitempty"`  // high, medium or low
+}
+
+func (x *DataQuality) Reset() {
+	*x = DataQuality{}
+	mi := &file_feargreed_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataQuality) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataQuality) ProtoMessage() {}
+
+func (x *DataQuality) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataQuality.ProtoReflect.Descriptor instead.
+func (*DataQuality) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DataQuality) GetWindow() int32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *DataQuality) GetSamples() int32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *DataQuality) GetComponents() int32 {
+	if x != nil {
+		return x.Components
+	}
+	return 0
+}
+
+func (x *DataQuality) GetCoverage() float64 {
+	if x != nil {
+		return x.Coverage
+	}
+	return 0
+}
+
+func (x *DataQuality) GetConfidence() string {
+	if x != nil {
+		return x.Confidence
+	}
+	return ""
+}
+
+type GetScoreResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker      string         `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Frequency   string         `protobuf:"bytes,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Latest      *Score         `protobuf:"bytes,3,opt,name=latest,proto3" json:"latest,omitempty"` // unset when the last bar has no score yet
+	Series      []*SeriesPoint `protobuf:"bytes,4,rep,name=series,proto3" json:"series,omitempty"`
+	LabelScheme *LabelScheme   `protobuf:"bytes,5,opt,name=label_scheme,json=labelScheme,proto3" json:"label_scheme,omitempty"`
+	Smoothing   *Smoothing     `protobuf:"bytes,6,opt,name=smoothing,proto3" json:"smoothing,omitempty"`     // unset without smoothing
+	Divergences []*Divergence  `protobuf:"bytes,7,rep,name=divergences,proto3" json:"divergences,omitempty"` // with divergence only, ending in the returned range
+	Regimes     []*RegimeSpan  `protobuf:"bytes,8,rep,name=regimes,proto3" json:"regimes,omitempty"`         // regime history of the returned range, oldest first
+	Fusion      *Fusion        `protobuf:"bytes,9,opt,name=fusion,proto3" json:"fusion,omitempty"`           // with timeframes only
+}
+
+func (x *GetScoreResponse) Reset() {
+	*x = GetScoreResponse{}
+	mi := &file_feargreed_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScoreResponse) ProtoMessage() {}
+
+func (x *GetScoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScoreResponse.ProtoReflect.Descriptor instead.
+func (*GetScoreResponse) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetScoreResponse) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *GetScoreResponse) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *GetScoreResponse) GetLatest() *Score {
+	if x != nil {
+		return x.Latest
+	}
+	return nil
+}
+
+func (x *GetScoreResponse) GetSeries() []*SeriesPoint {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *GetScoreResponse) GetLabelScheme() *LabelScheme {
+	if x != nil {
+		return x.LabelScheme
+	}
+	return nil
+}
+
+func (x *GetScoreResponse) GetSmoothing() *Smoothing {
+	if x != nil {
+		return x.Smoothing
+	}
+	return nil
+}
+
+func (x *GetScoreResponse) GetDivergences() []*Divergence {
+	if x != nil {
+		return x.Divergences
+	}
+	return nil
+}
+
+func (x *GetScoreResponse) GetRegimes() []*RegimeSpan {
+	if x != nil {
+		return x.Regimes
+	}
+	return nil
+}
+
+func (x *GetScoreResponse) GetFusion() *Fusion {
+	if x != nil {
+		return x.Fusion
+	}
+	return nil
+}
+
+// Fusion lists the timeframes of a fused score, weights adding up to 1.
+type Fusion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timeframes []*TimeframeWeight `protobuf:"bytes,1,rep,name=timeframes,proto3" json:"timeframes,omitempty"`
+}
+
+func (x *Fusion) Reset() {
+	*x = Fusion{}
+	mi := &file_feargreed_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fusion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fusion) ProtoMessage() {}
+
+func (x *Fusion) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fusion.ProtoReflect.Descriptor instead.
+func (*Fusion) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Fusion) GetTimeframes() []*TimeframeWeight {
+	if x != nil {
+		return x.Timeframes
+	}
+	return nil
+}
+
+type TimeframeWeight struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Freq   string  `protobuf:"bytes,1,opt,name=freq,proto3" json:"freq,omitempty"`
+	Weight float64 `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
+}
+
+func (x *TimeframeWeight) Reset() {
+	*x = TimeframeWeight{}
+	mi := &file_feargreed_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeframeWeight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeframeWeight) ProtoMessage() {}
+
+func (x *TimeframeWeight) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeframeWeight.ProtoReflect.Descriptor instead.
+func (*TimeframeWeight) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TimeframeWeight) GetFreq() string {
+	if x != nil {
+		return x.Freq
+	}
+	return ""
+}
+
+func (x *TimeframeWeight) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+// RegimeSpan is a run of bars in one market regime, start and end inclusive.
+type RegimeSpan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Regime string `protobuf:"bytes,1,opt,name=regime,proto3" json:"regime,omitempty"`
+	Name   string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // in the requested language
+	Start  string `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End    string `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	Bars   int32  `protobuf:"varint,5,opt,name=bars,proto3" json:"bars,omitempty"`
+}
+
+func (x *RegimeSpan) Reset() {
+	*x = RegimeSpan{}
+	mi := &file_feargreed_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegimeSpan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegimeSpan) ProtoMessage() {}
+
+func (x *RegimeSpan) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegimeSpan.ProtoReflect.Descriptor instead.
+func (*RegimeSpan) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RegimeSpan) GetRegime() string {
+	if x != nil {
+		return x.Regime
+	}
+	return ""
+}
+
+func (x *RegimeSpan) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegimeSpan) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *RegimeSpan) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *RegimeSpan) GetBars() int32 {
+	if x != nil {
+		return x.Bars
+	}
+	return 0
+}
+
+// Divergence is price and an indicator disagreeing between two swing pivots:
+// bearish for a higher price high with a lower indicator high, bullish for a
+// lower price low with a higher indicator low.
+type Divergence struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind        string  `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // bearish or bullish
+	Indicator   string  `protobuf:"bytes,2,opt,name=indicator,proto3" json:"indicator,omitempty"`
+	Start       string  `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End         string  `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	StartPrice  float64 `protobuf:"fixed64,5,opt,name=start_price,json=startPrice,proto3" json:"start_price,omitempty"`
+	EndPrice    float64 `protobuf:"fixed64,6,opt,name=end_price,json=endPrice,proto3" json:"end_price,omitempty"`
+	StartValue  float64 `protobuf:"fixed64,7,opt,name=start_value,json=startValue,proto3" json:"start_value,omitempty"`
+	EndValue    float64 `protobuf:"fixed64,8,opt,name=end_value,json=endValue,proto3" json:"end_value,omitempty"`
+	PriceChange float64 `protobuf:"fixed64,9,opt,name=price_change,json=priceChange,proto3" json:"price_change,omitempty"` // relative, 0.05 is +5%
+	Strength    float64 `protobuf:"fixed64,10,opt,name=strength,proto3" json:"strength,omitempty"`                         // points the indicator moved against price
+	Confirmed   string  `protobuf:"bytes,11,opt,name=confirmed,proto3" json:"confirmed,omitempty"`                         // bar the end pivot is confirmed on, pivot bars after end
+}
+
+func (x *Divergence) Reset() {
+	*x = Divergence{}
+	mi := &file_feargreed_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Divergence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Divergence) ProtoMessage() {}
+
+func (x *Divergence) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Divergence.ProtoReflect.Descriptor instead.
+func (*Divergence) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Divergence) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Divergence) GetIndicator() string {
+	if x != nil {
+		return x.Indicator
+	}
+	return ""
+}
+
+func (x *Divergence) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Divergence) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *Divergence) GetStartPrice() float64 {
+	if x != nil {
+		return x.StartPrice
+	}
+	return 0
+}
+
+func (x *Divergence) GetEndPrice() float64 {
+	if x != nil {
+		return x.EndPrice
+	}
+	return 0
+}
+
+func (x *Divergence) GetStartValue() float64 {
+	if x != nil {
+		return x.StartValue
+	}
+	return 0
+}
+
+func (x *Divergence) GetEndValue() float64 {
+	if x != nil {
+		return x.EndValue
+	}
+	return 0
+}
+
+func (x *Divergence) GetPriceChange() float64 {
+	if x != nil {
+		return x.PriceChange
+	}
+	return 0
+}
+
+func (x *Divergence) GetStrength() float64 {
+	if x != nil {
+		return x.Strength
+	}
+	return 0
+}
+
+func (x *Divergence) GetConfirmed() string {
+	if x != nil {
+		return x.Confirmed
+	}
+	return ""
+}
+
+type Smoothing struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Period int32  `protobuf:"varint,2,opt,name=period,proto3" json:"period,omitempty"`
+}
+
+func (x *Smoothing) Reset() {
+	*x = Smoothing{}
+	mi := &file_feargreed_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Smoothing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Smoothing) ProtoMessage() {}
+
+func (x *Smoothing) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Smoothing.ProtoReflect.Descriptor instead.
+func (*Smoothing) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Smoothing) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Smoothing) GetPeriod() int32 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+// LabelScheme is the scheme the labels were assigned with. A label only moves
+// once the score clears a cut-off by hysteresis points for min_dwell bars.
+type LabelScheme struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Hysteresis float64      `protobuf:"fixed64,2,opt,name=hysteresis,proto3" json:"hysteresis,omitempty"`
+	MinDwell   int32        `protobuf:"varint,3,opt,name=min_dwell,json=minDwell,proto3" json:"min_dwell,omitempty"`
+	Bands      []*LabelBand `protobuf:"bytes,4,rep,name=bands,proto3" json:"bands,omitempty"`
+}
+
+func (x *LabelScheme) Reset() {
+	*x = LabelScheme{}
+	mi := &file_feargreed_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LabelScheme) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelScheme) ProtoMessage() {}
+
+func (x *LabelScheme) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelScheme.ProtoReflect.Descriptor instead.
+func (*LabelScheme) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LabelScheme) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LabelScheme) GetHysteresis() float64 {
+	if x != nil {
+		return x.Hysteresis
+	}
+	return 0
+}
+
+func (x *LabelScheme) GetMinDwell() int32 {
+	if x != nil {
+		return x.MinDwell
+	}
+	return 0
+}
+
+func (x *LabelScheme) GetBands() []*LabelBand {
+	if x != nil {
+		return x.Bands
+	}
+	return nil
+}
+
+// LabelBand covers scores from from to to, to itself only when inclusive.
+type LabelBand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key       string  `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Name      string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	From      float64 `protobuf:"fixed64,3,opt,name=from,proto3" json:"from,omitempty"`
+	To        float64 `protobuf:"fixed64,4,opt,name=to,proto3" json:"to,omitempty"`
+	Inclusive bool    `protobuf:"varint,5,opt,name=inclusive,proto3" json:"inclusive,omitempty"`
+}
+
+func (x *LabelBand) Reset() {
+	*x = LabelBand{}
+	mi := &file_feargreed_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LabelBand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelBand) ProtoMessage() {}
+
+func (x *LabelBand) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelBand.ProtoReflect.Descriptor instead.
+func (*LabelBand) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LabelBand) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LabelBand) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LabelBand) GetFrom() float64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *LabelBand) GetTo() float64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *LabelBand) GetInclusive() bool {
+	if x != nil {
+		return x.Inclusive
+	}
+	return false
+}
+
+type GetHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker string `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Freq   string `protobuf:"bytes,2,opt,name=freq,proto3" json:"freq,omitempty"`   // 1d (default) or 1h
+	Start  string `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"` // YYYY-MM-DD
+	End    string `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`     // YYYY-MM-DD
+	Lang   string `protobuf:"bytes,5,opt,name=lang,proto3" json:"lang,omitempty"`   // zh, zh-TW, en or ja
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	mi := &file_feargreed_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetHistoryRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetFreq() string {
+	if x != nil {
+		return x.Freq
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+// PublishedScore is an archived score as the scheduler published it.
+type PublishedScore struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Score       *Score `protobuf:"bytes,1,opt,name=score,proto3" json:"score,omitempty"`
+	NormWindow  int32  `protobuf:"varint,2,opt,name=norm_window,json=normWindow,proto3" json:"norm_window,omitempty"`
+	Bars        int32  `protobuf:"varint,3,opt,name=bars,proto3" json:"bars,omitempty"`
+	PublishedAt string `protobuf:"bytes,4,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"` // RFC 3339
+}
+
+func (x *PublishedScore) Reset() {
+	*x = PublishedScore{}
+	mi := &file_feargreed_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishedScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishedScore) ProtoMessage() {}
+
+func (x *PublishedScore) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishedScore.ProtoReflect.Descriptor instead.
+func (*PublishedScore) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PublishedScore) GetScore() *Score {
+	if x != nil {
+		return x.Score
+	}
+	return nil
+}
+
+func (x *PublishedScore) GetNormWindow() int32 {
+	if x != nil {
+		return x.NormWindow
+	}
+	return 0
+}
+
+func (x *PublishedScore) GetBars() int32 {
+	if x != nil {
+		return x.Bars
+	}
+	return 0
+}
+
+func (x *PublishedScore) GetPublishedAt() string {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return ""
+}
+
+type GetHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker    string            `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Frequency string            `protobuf:"bytes,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Series    []*PublishedScore `protobuf:"bytes,3,rep,name=series,proto3" json:"series,omitempty"`
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_feargreed_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetHistoryResponse) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *GetHistoryResponse) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *GetHistoryResponse) GetSeries() []*PublishedScore {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+// BatchGetScoresRequest scores every item, fields left empty in an item are
+// taken from defaults.
+type BatchGetScoresRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items    []*GetScoreRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Defaults *GetScoreRequest   `protobuf:"bytes,2,opt,name=defaults,proto3" json:"defaults,omitempty"`
+}
+
+func (x *BatchGetScoresRequest) Reset() {
+	*x = BatchGetScoresRequest{}
+	mi := &file_feargreed_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetScoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetScoresRequest) ProtoMessage() {}
+
+func (x *BatchGetScoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetScoresRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetScoresRequest) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchGetScoresRequest) GetItems() []*GetScoreRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchGetScoresRequest) GetDefaults() *GetScoreRequest {
+	if x != nil {
+		return x.Defaults
+	}
+	return nil
+}
+
+type Error struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"` // same codes as the JSON API, e.g. not_found
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_feargreed_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BatchItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index  int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Ticker string `protobuf:"bytes,2,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	// Types that are assignable to Outcome:
+	//	*BatchItem_Result
+	//	*BatchItem_Error
+	Outcome isBatchItem_Outcome `protobuf_oneof:"outcome"`
+}
+
+func (x *BatchItem) Reset() {
+	*x = BatchItem{}
+	mi := &file_feargreed_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItem) ProtoMessage() {}
+
+func (x *BatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItem.ProtoReflect.Descriptor instead.
+func (*BatchItem) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BatchItem) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchItem) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (m *BatchItem) GetOutcome() isBatchItem_Outcome {
+	if m != nil {
+		return m.Outcome
+	}
+	return nil
+}
+
+func (x *BatchItem) GetResult() *GetScoreResponse {
+	if x, ok := x.GetOutcome().(*BatchItem_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchItem) GetError() *Error {
+	if x, ok := x.GetOutcome().(*BatchItem_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+type isBatchItem_Outcome interface {
+	isBatchItem_Outcome()
+}
+
+type BatchItem_Result struct {
+	Result *GetScoreResponse `protobuf:"bytes,3,opt,name=result,proto3,oneof"`
+}
+
+type BatchItem_Error struct {
+	Error *Error `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchItem_Result) isBatchItem_Outcome() {}
+
+func (*BatchItem_Error) isBatchItem_Outcome() {}
+
+// BatchGetScoresResponse has the items in request order.
+type BatchGetScoresResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*BatchItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *BatchGetScoresResponse) Reset() {
+	*x = BatchGetScoresResponse{}
+	mi := &file_feargreed_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetScoresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetScoresResponse) ProtoMessage() {}
+
+func (x *BatchGetScoresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetScoresResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetScoresResponse) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BatchGetScoresResponse) GetItems() []*BatchItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type WatchScoresRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tickers []string `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+	Freq    string   `protobuf:"bytes,2,opt,name=freq,proto3" json:"freq,omitempty"` // 1d (default) or 1h
+	Lang    string   `protobuf:"bytes,3,opt,name=lang,proto3" json:"lang,omitempty"` // zh, zh-TW, en or ja
+}
+
+func (x *WatchScoresRequest) Reset() {
+	*x = WatchScoresRequest{}
+	mi := &file_feargreed_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchScoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchScoresRequest) ProtoMessage() {}
+
+func (x *WatchScoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchScoresRequest.ProtoReflect.Descriptor instead.
+func (*WatchScoresRequest) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchScoresRequest) GetTickers() []string {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+func (x *WatchScoresRequest) GetFreq() string {
+	if x != nil {
+		return x.Freq
+	}
+	return ""
+}
+
+func (x *WatchScoresRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+// ScoreUpdate is the latest bar of a watched ticker. score is unset and
+// subscores empty while the ticker warms up.
+type ScoreUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker     string             `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Frequency  string             `protobuf:"bytes,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Date       string             `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	Score      *float64           `protobuf:"fixed64,4,opt,name=score,proto3,oneof" json:"score,omitempty"`
+	Label      string             `protobuf:"bytes,5,opt,name=label,proto3" json:"label,omitempty"`
+	Price      float64            `protobuf:"fixed64,6,opt,name=price,proto3" json:"price,omitempty"`
+	Subscores  map[string]float64 `protobuf:"bytes,7,rep,name=subscores,proto3" json:"subscores,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Band       string             `protobuf:"bytes,8,opt,name=band,proto3" json:"band,omitempty"`              // label band key of the default scheme
+	Regime     string             `protobuf:"bytes,9,opt,name=regime,proto3" json:"regime,omitempty"`          // market regime
+	Confidence string             `protobuf:"bytes,10,opt,name=confidence,proto3" json:"confidence,omitempty"` // high, medium or low
+}
+
+func (x *ScoreUpdate) Reset() {
+	*x = ScoreUpdate{}
+	mi := &file_feargreed_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreUpdate) ProtoMessage() {}
+
+func (x *ScoreUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_feargreed_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreUpdate.ProtoReflect.Descriptor instead.
+func (*ScoreUpdate) Descriptor() ([]byte, []int) {
+	return file_feargreed_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ScoreUpdate) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *ScoreUpdate) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *ScoreUpdate) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ScoreUpdate) GetScore() float64 {
+	if x != nil && x.Score != nil {
+		return *x.Score
+	}
+	return 0
+}
+
+func (x *ScoreUpdate) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *ScoreUpdate) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ScoreUpdate) GetSubscores() map[string]float64 {
+	if x != nil {
+		return x.Subscores
+	}
+	return nil
+}
+
+func (x *ScoreUpdate) GetBand() string {
+	if x != nil {
+		return x.Band
+	}
+	return ""
+}
+
+func (x *ScoreUpdate) GetRegime() string {
+	if x != nil {
+		return x.Regime
+	}
+	return ""
+}
+
+func (x *ScoreUpdate) GetConfidence() string {
+	if x != nil {
+		return x.Confidence
+	}
+	return ""
+}
+
+var File_feargreed_proto protoreflect.FileDescriptor
+
+var file_feargreed_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0c, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x22,
+	0xf6, 0x02, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x66,
+	0x72, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x65, 0x71, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x73, 0x6f, 0x66, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x73, 0x6f, 0x66, 0x12, 0x16, 0x0a, 0x06, 0x77,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x6e, 0x67, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x61, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6d, 0x6f, 0x6f, 0x74, 0x68, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6d, 0x6f, 0x6f, 0x74, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x73,
+	0x6d, 0x6f, 0x6f, 0x74, 0x68, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0c, 0x73, 0x6d, 0x6f, 0x6f, 0x74, 0x68, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64,
+	0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x76, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x76, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x70, 0x69, 0x76, 0x6f, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x70, 0x69, 0x76, 0x6f, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x74, 0x66, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x03, 0x6d, 0x74, 0x66, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65,
+	0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x69,
+	0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x22, 0xc0, 0x03, 0x0a, 0x0b, 0x53, 0x65, 0x72,
+	0x69, 0x65, 0x73, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x05,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x05, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x14, 0x0a,
+	0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72,
+	0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x61, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x62, 0x61, 0x6e, 0x64, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x6c, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x67, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x67, 0x69,
+	0x6d, 0x65, 0x12, 0x19, 0x0a, 0x05, 0x66, 0x75, 0x73, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x01, 0x48, 0x02, 0x52, 0x05, 0x66, 0x75, 0x73, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a,
+	0x0a, 0x74, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x29, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x74, 0x69,
+	0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x07, 0x71, 0x75, 0x61, 0x6c,
+	0x69, 0x74, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x66, 0x65, 0x61, 0x72,
+	0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x51, 0x75, 0x61,
+	0x6c, 0x69, 0x74, 0x79, 0x52, 0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x1a, 0x3d, 0x0a,
+	0x0f, 0x54, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x6c, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x66, 0x75, 0x73, 0x65, 0x64, 0x22, 0x8f, 0x04, 0x0a, 0x05,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x73,
+	0x75, 0x62, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x09, 0x73, 0x75, 0x62, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x62, 0x61, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x61, 0x6e,
+	0x64, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x01, 0x48, 0x00, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x67, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x05, 0x66, 0x75, 0x73, 0x65, 0x64, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x05, 0x66, 0x75, 0x73, 0x65, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x43, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x18,
+	0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65,
+	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x66,
+	0x72, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65,
+	0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74,
+	0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72,
+	0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x51, 0x75, 0x61, 0x6c, 0x69,
+	0x74, 0x79, 0x52, 0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x1a, 0x3c, 0x0a, 0x0e, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3d, 0x0a, 0x0f, 0x54, 0x69, 0x6d,
+	0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x6c, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x66, 0x75, 0x73, 0x65, 0x64, 0x22, 0x9b, 0x01,
+	0x0a, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x77,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x22, 0xbb, 0x03, 0x0a, 0x10,
+	0x47, 0x65, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65,
+	0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x06, 0x6c, 0x61, 0x74,
+	0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06,
+	0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x3c, 0x0a, 0x0c, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x5f,
+	0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x66,
+	0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x52, 0x0b, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x09, 0x73, 0x6d, 0x6f, 0x6f, 0x74, 0x68, 0x69, 0x6e,
+	0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72,
+	0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6d, 0x6f, 0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67,
+	0x52, 0x09, 0x73, 0x6d, 0x6f, 0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x12, 0x3a, 0x0a, 0x0b, 0x64,
+	0x69, 0x76, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e,
+	0x44, 0x69, 0x76, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0b, 0x64, 0x69, 0x76, 0x65,
+	0x72, 0x67, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x07, 0x72, 0x65, 0x67, 0x69, 0x6d,
+	0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67,
+	0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x6d, 0x65, 0x53, 0x70,
+	0x61, 0x6e, 0x52, 0x07, 0x72, 0x65, 0x67, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x06, 0x66,
+	0x75, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x66, 0x65,
+	0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x75, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x06, 0x66, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x47, 0x0a, 0x06, 0x46, 0x75, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72,
+	0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65,
+	0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d,
+	0x65, 0x73, 0x22, 0x3d, 0x0a, 0x0f, 0x54, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x57,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x65, 0x71, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x22, 0x74, 0x0a, 0x0a, 0x52, 0x65, 0x67, 0x69, 0x6d, 0x65, 0x53, 0x70, 0x61, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x67, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x61, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x62, 0x61, 0x72, 0x73, 0x22, 0xbf, 0x02, 0x0a, 0x0a, 0x44, 0x69, 0x76, 0x65,
+	0x72, 0x67, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e,
+	0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69,
+	0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10,
+	0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64,
+	0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x72, 0x69, 0x63,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x6e, 0x64, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1f,
+	0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x65, 0x6e, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x73, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x73, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x22, 0x3b, 0x0a, 0x09, 0x53, 0x6d, 0x6f,
+	0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x22, 0x89, 0x01, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x68, 0x79, 0x73, 0x74, 0x65, 0x72,
+	0x65, 0x73, 0x69, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x68, 0x79, 0x73, 0x74,
+	0x65, 0x72, 0x65, 0x73, 0x69, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x64, 0x77,
+	0x65, 0x6c, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x44, 0x77,
+	0x65, 0x6c, 0x6c, 0x12, 0x2d, 0x0a, 0x05, 0x62, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x42, 0x61, 0x6e, 0x64, 0x52, 0x05, 0x62, 0x61, 0x6e,
+	0x64, 0x73, 0x22, 0x73, 0x0a, 0x09, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x42, 0x61, 0x6e, 0x64, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x6e,
+	0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x22, 0x7b, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x69,
+	0x63, 0x6b, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10,
+	0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6c, 0x61, 0x6e, 0x67, 0x22, 0x93, 0x01, 0x0a, 0x0e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x65, 0x64, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65,
+	0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x05, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x72, 0x6d, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6e, 0x6f, 0x72, 0x6d, 0x57, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x61, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x62, 0x61, 0x72, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x73, 0x68, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70,
+	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x41, 0x74, 0x22, 0x80, 0x01, 0x0a, 0x12, 0x47,
+	0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x34, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72,
+	0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x22, 0x87, 0x01,
+	0x0a, 0x15, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65,
+	0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x39, 0x0a, 0x08,
+	0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x64,
+	0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x35, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xab,
+	0x01, 0x0a, 0x09, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x14, 0x0a, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x38, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x65, 0x61,
+	0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f,
+	0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x2b, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x42, 0x09, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x22, 0x47, 0x0a, 0x16,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65,
+	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x56, 0x0a, 0x12, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x63,
+	0x6f, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74,
+	0x69, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69,
+	0x63, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x65, 0x71, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x6e,
+	0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x61, 0x6e, 0x67, 0x22, 0xfa, 0x02,
+	0x0a, 0x0b, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74,
+	0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x46,
+	0x0a, 0x09, 0x73, 0x75, 0x62, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x28, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x75, 0x62,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x73, 0x75, 0x62,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x61, 0x6e, 0x64, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x61, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x67, 0x69, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x67, 0x69,
+	0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e,
+	0x63, 0x65, 0x1a, 0x3c, 0x0a, 0x0e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x32, 0xd9, 0x02, 0x0a, 0x10, 0x46,
+	0x65, 0x61, 0x72, 0x47, 0x72, 0x65, 0x65, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x49, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1d, 0x2e, 0x66, 0x65,
+	0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x63,
+	0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x66, 0x65, 0x61,
+	0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f,
+	0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0a, 0x47, 0x65,
+	0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1f, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67,
+	0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x66, 0x65, 0x61, 0x72,
+	0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x0e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x23, 0x2e,
+	0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x24, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x0b, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x20, 0x2e, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72,
+	0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x63, 0x6f, 0x72,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x66, 0x65, 0x61, 0x72,
+	0x67, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x30, 0x01, 0x42, 0x45, 0x5a, 0x43, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x77, 0x61, 0x6e, 0x6c, 0x65, 0x62, 0x72, 0x6f, 0x6e, 0x2f,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x2d, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x2f, 0x76,
+	0x31, 0x3b, 0x66, 0x65, 0x61, 0x72, 0x67, 0x72, 0x65, 0x65, 0x64, 0x76, 0x31, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_feargreed_proto_rawDescOnce sync.Once
+	file_feargreed_proto_rawDescData = file_feargreed_proto_rawDesc
+)
+
+func file_feargreed_proto_rawDescGZIP() []byte {
+	file_feargreed_proto_rawDescOnce.Do(func() {
+		file_feargreed_proto_rawDescData = protoimpl.X.CompressGZIP(file_feargreed_proto_rawDescData)
+	})
+	return file_feargreed_proto_rawDescData
+}
+
+var file_feargreed_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_feargreed_proto_goTypes = []any{
+	(*GetScoreRequest)(nil),        // 0: feargreed.v1.GetScoreRequest
+	(*SeriesPoint)(nil),            // 1: feargreed.v1.SeriesPoint
+	(*Score)(nil),                  // 2: feargreed.v1.Score
+	(*DataQuality)(nil),            // 3: feargreed.v1.DataQuality
+	(*GetScoreResponse)(nil),       // 4: feargreed.v1.GetScoreResponse
+	(*Fusion)(nil),                 // 5: feargreed.v1.Fusion
+	(*TimeframeWeight)(nil),        // 6: feargreed.v1.TimeframeWeight
+	(*RegimeSpan)(nil),             // 7: feargreed.v1.RegimeSpan
+	(*Divergence)(nil),             // 8: feargreed.v1.Divergence
+	(*Smoothing)(nil),              // 9: feargreed.v1.Smoothing
+	(*LabelScheme)(nil),            // 10: feargreed.v1.LabelScheme
+	(*LabelBand)(nil),              // 11: feargreed.v1.LabelBand
+	(*GetHistoryRequest)(nil),      // 12: feargreed.v1.GetHistoryRequest
+	(*PublishedScore)(nil),         // 13: feargreed.v1.PublishedScore
+	(*GetHistoryResponse)(nil),     // 14: feargreed.v1.GetHistoryResponse
+	(*BatchGetScoresRequest)(nil),  // 15: feargreed.v1.BatchGetScoresRequest
+	(*Error)(nil),                  // 16: feargreed.v1.Error
+	(*BatchItem)(nil),              // 17: feargreed.v1.BatchItem
+	(*BatchGetScoresResponse)(nil), // 18: feargreed.v1.BatchGetScoresResponse
+	(*WatchScoresRequest)(nil),     // 19: feargreed.v1.WatchScoresRequest
+	(*ScoreUpdate)(nil),            // 20: feargreed.v1.ScoreUpdate
+	nil,                            // 21: feargreed.v1.SeriesPoint.TimeframesEntry
+	nil,                            // 22: feargreed.v1.Score.SubscoresEntry
+	nil,                            // 23: feargreed.v1.Score.TimeframesEntry
+	nil,                            // 24: feargreed.v1.ScoreUpdate.SubscoresEntry
+}
+var file_feargreed_proto_depIdxs = []int32{
+	21, // 0: feargreed.v1.SeriesPoint.timeframes:type_name -> feargreed.v1.SeriesPoint.TimeframesEntry
+	3,  // 1: feargreed.v1.SeriesPoint.quality:type_name -> feargreed.v1.DataQuality
+	22, // 2: feargreed.v1.Score.subscores:type_name -> feargreed.v1.Score.SubscoresEntry
+	23, // 3: feargreed.v1.Score.timeframes:type_name -> feargreed.v1.Score.TimeframesEntry
+	3,  // 4: feargreed.v1.Score.quality:type_name -> feargreed.v1.DataQuality
+	2,  // 5: feargreed.v1.GetScoreResponse.latest:type_name -> feargreed.v1.Score
+	1,  // 6: feargreed.v1.GetScoreResponse.series:type_name -> feargreed.v1.SeriesPoint
+	10, // 7: feargreed.v1.GetScoreResponse.label_scheme:type_name -> feargreed.v1.LabelScheme
+	9,  // 8: feargreed.v1.GetScoreResponse.smoothing:type_name -> feargreed.v1.Smoothing
+	8,  // 9: feargreed.v1.GetScoreResponse.divergences:type_name -> feargreed.v1.Divergence
+	7,  // 10: feargreed.v1.GetScoreResponse.regimes:type_name -> feargreed.v1.RegimeSpan
+	5,  // 11: feargreed.v1.GetScoreResponse.fusion:type_name -> feargreed.v1.Fusion
+	6,  // 12: feargreed.v1.Fusion.timeframes:type_name -> feargreed.v1.TimeframeWeight
+	11, // 13: feargreed.v1.LabelScheme.bands:type_name -> feargreed.v1.LabelBand
+	2,  // 14: feargreed.v1.PublishedScore.score:type_name -> feargreed.v1.Score
+	13, // 15: feargreed.v1.GetHistoryResponse.series:type_name -> feargreed.v1.PublishedScore
+	0,  // 16: feargreed.v1.BatchGetScoresRequest.items:type_name -> feargreed.v1.GetScoreRequest
+	0,  // 17: feargreed.v1.BatchGetScoresRequest.defaults:type_name -> feargreed.v1.GetScoreRequest
+	4,  // 18: feargreed.v1.BatchItem.result:type_name -> feargreed.v1.GetScoreResponse
+	16, // 19: feargreed.v1.BatchItem.error:type_name -> feargreed.v1.Error
+	17, // 20: feargreed.v1.BatchGetScoresResponse.items:type_name -> feargreed.v1.BatchItem
+	24, // 21: feargreed.v1.ScoreUpdate.subscores:type_name -> feargreed.v1.ScoreUpdate.SubscoresEntry
+	0,  // 22: feargreed.v1.FearGreedService.GetScore:input_type -> feargreed.v1.GetScoreRequest
+	12, // 23: feargreed.v1.FearGreedService.GetHistory:input_type -> feargreed.v1.GetHistoryRequest
+	15, // 24: feargreed.v1.FearGreedService.BatchGetScores:input_type -> feargreed.v1.BatchGetScoresRequest
+	19, // 25: feargreed.v1.FearGreedService.WatchScores:input_type -> feargreed.v1.WatchScoresRequest
+	4,  // 26: feargreed.v1.FearGreedService.GetScore:output_type -> feargreed.v1.GetScoreResponse
+	14, // 27: feargreed.v1.FearGreedService.GetHistory:output_type -> feargreed.v1.GetHistoryResponse
+	18, // 28: feargreed.v1.FearGreedService.BatchGetScores:output_type -> feargreed.v1.BatchGetScoresResponse
+	20, // 29: feargreed.v1.FearGreedService.WatchScores:output_type -> feargreed.v1.ScoreUpdate
+	26, // [26:30] is the sub-list for method output_type
+	22, // [22:26] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_feargreed_proto_init() }
+func file_feargreed_proto_init() {
+	if File_feargreed_proto != nil {
+		return
+	}
+	file_feargreed_proto_msgTypes[1].OneofWrappers = []any{}
+	file_feargreed_proto_msgTypes[2].OneofWrappers = []any{}
+	file_feargreed_proto_msgTypes[17].OneofWrappers = []any{
+		(*BatchItem_Result)(nil),
+		(*BatchItem_Error)(nil),
+	}
+	file_feargreed_proto_msgTypes[20].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_feargreed_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_feargreed_proto_goTypes,
+		DependencyIndexes: file_feargreed_proto_depIdxs,
+		MessageInfos:      file_feargreed_proto_msgTypes,
+	}.Build()
+	File_feargreed_proto = out.File
+	file_feargreed_proto_rawDesc = nil
+	file_feargreed_proto_goTypes = nil
+	file_feargreed_proto_depIdxs = nil
+}
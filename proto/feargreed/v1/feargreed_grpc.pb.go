@@ -0,0 +1,262 @@
+// Fear & Greed scores over gRPC. The messages mirror the JSON API: dates are
+// exchange-local YYYY-MM-DD strings for daily bars and RFC 3339 for intraday,
+// scores run from 0 (extreme fear) to 100 (extreme greed).
+//
+// Regenerate the Go code with `go generate ./proto/...`, which needs protoc,
+// protoc-gen-go and protoc-gen-go-grpc on the PATH.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: feargreed.proto
+
+package feargreedv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FearGreedService_GetScore_FullMethodName       = "/feargreed.v1.FearGreedService/GetScore"
+	FearGreedService_GetHistory_FullMethodName     = "/feargreed.v1.FearGreedService/GetHistory"
+	FearGreedService_BatchGetScores_FullMethodName = "/feargreed.v1.FearGreedService/BatchGetScores"
+	FearGreedService_WatchScores_FullMethodName    = "/feargreed.v1.FearGreedService/WatchScores"
+)
+
+// FearGreedServiceClient is the client API for FearGreedService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FearGreedServiceClient interface {
+	// GetScore computes the score series of one ticker, like GET /api/v1/fear-greed.
+	GetScore(ctx context.Context, in *GetScoreRequest, opts ...grpc.CallOption) (*GetScoreResponse, error)
+	// GetHistory returns the archived "as published" scores, like GET
+	// /api/v1/fear-greed/history. UNAVAILABLE when archiving is disabled.
+	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+	// BatchGetScores scores many tickers, like POST /api/v1/scores:batch. A
+	// failed ticker is reported in its item, the call itself still succeeds.
+	BatchGetScores(ctx context.Context, in *BatchGetScoresRequest, opts ...grpc.CallOption) (*BatchGetScoresResponse, error)
+	// WatchScores pushes the latest score of each ticker whenever it changes,
+	// like GET /api/v1/stream. The first message of every ticker is its
+	// current score.
+	WatchScores(ctx context.Context, in *WatchScoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ScoreUpdate], error)
+}
+
+type fearGreedServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFearGreedServiceClient(cc grpc.ClientConnInterface) FearGreedServiceClient {
+	return &fearGreedServiceClient{cc}
+}
+
+func (c *fearGreedServiceClient) GetScore(ctx context.Context, in *GetScoreRequest, opts ...grpc.CallOption) (*GetScoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetScoreResponse)
+	err := c.cc.Invoke(ctx, FearGreedService_GetScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fearGreedServiceClient) GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHistoryResponse)
+	err := c.cc.Invoke(ctx, FearGreedService_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fearGreedServiceClient) BatchGetScores(ctx context.Context, in *BatchGetScoresRequest, opts ...grpc.CallOption) (*BatchGetScoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetScoresResponse)
+	err := c.cc.Invoke(ctx, FearGreedService_BatchGetScores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fearGreedServiceClient) WatchScores(ctx context.Context, in *WatchScoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ScoreUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FearGreedService_ServiceDesc.Streams[0], FearGreedService_WatchScores_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchScoresRequest, ScoreUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FearGreedService_WatchScoresClient = grpc.ServerStreamingClient[ScoreUpdate]
+
+// FearGreedServiceServer is the server API for FearGreedService service.
+// All implementations must embed UnimplementedFearGreedServiceServer
+// for forward compatibility.
+type FearGreedServiceServer interface {
+	// GetScore computes the score series of one ticker, like GET /api/v1/fear-greed.
+	GetScore(context.Context, *GetScoreRequest) (*GetScoreResponse, error)
+	// GetHistory returns the archived "as published" scores, like GET
+	// /api/v1/fear-greed/history. UNAVAILABLE when archiving is disabled.
+	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
+	// BatchGetScores scores many tickers, like POST /api/v1/scores:batch. A
+	// failed ticker is reported in its item, the call itself still succeeds.
+	BatchGetScores(context.Context, *BatchGetScoresRequest) (*BatchGetScoresResponse, error)
+	// WatchScores pushes the latest score of each ticker whenever it changes,
+	// like GET /api/v1/stream. The first message of every ticker is its
+	// current score.
+	WatchScores(*WatchScoresRequest, grpc.ServerStreamingServer[ScoreUpdate]) error
+	mustEmbedUnimplementedFearGreedServiceServer()
+}
+
+// UnimplementedFearGreedServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFearGreedServiceServer struct{}
+
+func (UnimplementedFearGreedServiceServer) GetScore(context.Context, *GetScoreRequest) (*GetScoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScore not implemented")
+}
+func (UnimplementedFearGreedServiceServer) GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedFearGreedServiceServer) BatchGetScores(context.Context, *BatchGetScoresRequest) (*BatchGetScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetScores not implemented")
+}
+func (UnimplementedFearGreedServiceServer) WatchScores(*WatchScoresRequest, grpc.ServerStreamingServer[ScoreUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchScores not implemented")
+}
+func (UnimplementedFearGreedServiceServer) mustEmbedUnimplementedFearGreedServiceServer() {}
+func (UnimplementedFearGreedServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeFearGreedServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FearGreedServiceServer will
+// result in compilation errors.
+type UnsafeFearGreedServiceServer interface {
+	mustEmbedUnimplementedFearGreedServiceServer()
+}
+
+func RegisterFearGreedServiceServer(s grpc.ServiceRegistrar, srv FearGreedServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFearGreedServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FearGreedService_ServiceDesc, srv)
+}
+
+func _FearGreedService_GetScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FearGreedServiceServer).GetScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FearGreedService_GetScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FearGreedServiceServer).GetScore(ctx, req.(*GetScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FearGreedService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FearGreedServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FearGreedService_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FearGreedServiceServer).GetHistory(ctx, req.(*GetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FearGreedService_BatchGetScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FearGreedServiceServer).BatchGetScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FearGreedService_BatchGetScores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FearGreedServiceServer).BatchGetScores(ctx, req.(*BatchGetScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FearGreedService_WatchScores_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchScoresRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FearGreedServiceServer).WatchScores(m, &grpc.GenericServerStream[WatchScoresRequest, ScoreUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FearGreedService_WatchScoresServer = grpc.ServerStreamingServer[ScoreUpdate]
+
+// FearGreedService_ServiceDesc is the grpc.ServiceDesc for FearGreedService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FearGreedService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "feargreed.v1.FearGreedService",
+	HandlerType: (*FearGreedServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetScore",
+			Handler:    _FearGreedService_GetScore_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _FearGreedService_GetHistory_Handler,
+		},
+		{
+			MethodName: "BatchGetScores",
+			Handler:    _FearGreedService_BatchGetScores_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchScores",
+			Handler:       _FearGreedService_WatchScores_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "feargreed.proto",
+}